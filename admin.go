@@ -0,0 +1,217 @@
+package gom
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// createCollectionOptions = build driver create collection options
+func (p *CreateTableParams) createCollectionOptions() (*options.CreateCollectionOptions, error) {
+	opts := options.CreateCollection()
+
+	if p == nil {
+		return opts, nil
+	}
+
+	if p.Capped {
+		if p.SizeInBytes <= 0 {
+			return nil, validationError("capped table requires SizeInBytes")
+		}
+
+		opts.SetCapped(true)
+		opts.SetSizeInBytes(p.SizeInBytes)
+
+		if p.MaxDocuments > 0 {
+			opts.SetMaxDocuments(p.MaxDocuments)
+		}
+	}
+
+	if p.TimeSeries != nil {
+		if p.TimeSeries.TimeField == "" {
+			return nil, validationError("time-series table requires TimeField")
+		}
+
+		ts := options.TimeSeries().SetTimeField(p.TimeSeries.TimeField)
+
+		if p.TimeSeries.MetaField != "" {
+			ts.SetMetaField(p.TimeSeries.MetaField)
+		}
+
+		if p.TimeSeries.Granularity != "" {
+			ts.SetGranularity(p.TimeSeries.Granularity)
+		}
+
+		opts.SetTimeSeriesOptions(ts)
+	}
+
+	if p.ExpireAfterSeconds != nil {
+		opts.SetExpireAfterSeconds(*p.ExpireAfterSeconds)
+	}
+
+	if p.Collation != nil {
+		opts.SetCollation(p.Collation)
+	}
+
+	if p.ClusteredIndex {
+		opts.SetClusteredIndex(bson.M{
+			"key":    bson.M{"_id": 1},
+			"unique": true,
+		})
+	}
+
+	if p.Validator != nil {
+		opts.SetValidator(p.Validator)
+	}
+
+	if p.ValidationLevel != "" {
+		opts.SetValidationLevel(p.ValidationLevel)
+	}
+
+	if p.ValidationAction != "" {
+		opts.SetValidationAction(p.ValidationAction)
+	}
+
+	return opts, nil
+}
+
+// CreateTable = create table/collection with options, params can be nil
+func (c *Command) CreateTable(params *CreateTableParams) error {
+	if c.set.tableName == "" {
+		return validationError("table name not defined")
+	}
+
+	opts, err := params.createCollectionOptions()
+
+	if err != nil {
+		return err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	err = client.Database(c.set.gom.GetDatabase()).CreateCollection(ctx, c.set.tableName, opts)
+
+	if err != nil {
+		return wrapError("Error creating collection", err)
+	}
+
+	return nil
+}
+
+// ListTables = list table/collection names of database. Filter of set is applied to collection info, eg: StartWith("name", "log_")
+func (c *Command) ListTables() ([]string, error) {
+	err := c.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	names, err := client.Database(c.set.gom.GetDatabase()).ListCollectionNames(ctx, c.set.filter)
+
+	if err != nil {
+		return nil, wrapError("Error listing collections", err)
+	}
+
+	return names, nil
+}
+
+// RenameTable = rename table/collection, dropTarget drops existing table with new name
+func (c *Command) RenameTable(newName string, dropTarget bool) error {
+	if c.set.tableName == "" {
+		return validationError("table name not defined")
+	}
+
+	if newName == "" {
+		return validationError("new table name can't be empty")
+	}
+
+	err := c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	database := c.set.gom.GetDatabase()
+
+	err = client.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "renameCollection", Value: database + "." + c.set.tableName},
+		{Key: "to", Value: database + "." + newName},
+		{Key: "dropTarget", Value: dropTarget},
+	}).Err()
+
+	if err != nil {
+		return wrapError("Error renaming collection", err)
+	}
+
+	c.set.tableName = newName
+
+	return nil
+}
+
+// TableStats = get collStats of table/collection
+func (c *Command) TableStats() (bson.M, error) {
+	if c.set.tableName == "" {
+		return nil, validationError("table name not defined")
+	}
+
+	return c.runCommand("Error getting collection stats", bson.D{
+		{Key: "collStats", Value: c.set.tableName},
+	})
+}
+
+// DatabaseStats = get dbStats of database
+func (c *Command) DatabaseStats() (bson.M, error) {
+	return c.runCommand("Error getting database stats", bson.D{
+		{Key: "dbStats", Value: 1},
+	})
+}
+
+// runCommand = run database command and decode its result
+func (c *Command) runCommand(message string, command bson.D) (bson.M, error) {
+	err := c.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	res := bson.M{}
+
+	err = client.Database(c.set.gom.GetDatabase()).RunCommand(ctx, command).Decode(&res)
+
+	if err != nil {
+		return nil, wrapError(message, err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,287 @@
+package gom
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Bulk = queue of mixed write operations executed in one bulk write
+type Bulk struct {
+	set     *Set
+	models  []mongo.WriteModel
+	ordered bool
+	err     error
+}
+
+// BulkWriteError = error of one operation in bulk, Index is position of the operation in queue
+type BulkWriteError struct {
+	Index   int
+	Code    int
+	Message string
+}
+
+// BulkResult = result of bulk write
+type BulkResult struct {
+	InsertedCount int64
+	MatchedCount  int64
+	ModifiedCount int64
+	DeletedCount  int64
+	UpsertedCount int64
+	// UpsertedIDs = upserted id by index of operation in queue
+	UpsertedIDs map[int64]interface{}
+	WriteErrors []BulkWriteError
+}
+
+// newBulk = create new bulk, ordered by default
+func newBulk(s *Set) *Bulk {
+	b := new(Bulk)
+	b.set = s
+	b.ordered = true
+
+	return b
+}
+
+// Bulk = create bulk write builder for table of this set
+func (s *Set) Bulk() *Bulk {
+	return newBulk(s)
+}
+
+// Ordered = set ordered mode. Ordered stops at first error, unordered continues and reports all errors. Default is true
+func (b *Bulk) Ordered(ordered bool) *Bulk {
+	b.ordered = ordered
+
+	return b
+}
+
+// Len = get number of queued operations
+func (b *Bulk) Len() int {
+	return len(b.models)
+}
+
+// fail = keep first error, returned by Execute
+func (b *Bulk) fail(index int, err error) *Bulk {
+	if b.err == nil {
+		b.err = &BulkOperationError{
+			Index: index,
+			Err:   err,
+		}
+	}
+
+	return b
+}
+
+// buildFilter = build filter of operation, can't be empty
+func (b *Bulk) buildFilter(filter *Filter) (bson.M, error) {
+	if filter == nil {
+		return nil, validationError("filter can't be empty")
+	}
+
+	main, err := Compile(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(main) == 0 {
+		return nil, validationError("filter can't be empty")
+	}
+
+	return main, nil
+}
+
+// Insert = queue insert of one struct/map pointer
+func (b *Bulk) Insert(data interface{}) *Bulk {
+	dataM, err := b.set.buildDocument(data, true)
+
+	if err != nil {
+		return b.fail(len(b.models), err)
+	}
+
+	b.models = append(b.models, mongo.NewInsertOneModel().SetDocument(dataM))
+
+	return b
+}
+
+// update = queue update one or many
+func (b *Bulk) update(filter *Filter, data interface{}, many, upsert bool) *Bulk {
+	filterM, err := b.buildFilter(filter)
+
+	if err != nil {
+		return b.fail(len(b.models), err)
+	}
+
+	update, opts, err := b.set.buildUpdate(data)
+
+	if err != nil {
+		return b.fail(len(b.models), err)
+	}
+
+	if many {
+		m := mongo.NewUpdateManyModel().SetFilter(filterM).SetUpdate(update).SetUpsert(upsert)
+
+		if opts.ArrayFilters != nil {
+			m.SetArrayFilters(*opts.ArrayFilters)
+		}
+
+		b.models = append(b.models, m)
+	} else {
+		m := mongo.NewUpdateOneModel().SetFilter(filterM).SetUpdate(update).SetUpsert(upsert)
+
+		if opts.ArrayFilters != nil {
+			m.SetArrayFilters(*opts.ArrayFilters)
+		}
+
+		b.models = append(b.models, m)
+	}
+
+	return b
+}
+
+// UpdateOne = queue update of first document matching filter. Data is struct/map pointer to $set, or *Update
+func (b *Bulk) UpdateOne(filter *Filter, data interface{}) *Bulk {
+	return b.update(filter, data, false, false)
+}
+
+// UpdateAll = queue update of all documents matching filter. Data is struct/map pointer to $set, or *Update
+func (b *Bulk) UpdateAll(filter *Filter, data interface{}) *Bulk {
+	return b.update(filter, data, true, false)
+}
+
+// Upsert = queue update of first document matching filter, or insert if there is none
+func (b *Bulk) Upsert(filter *Filter, data interface{}) *Bulk {
+	return b.update(filter, data, false, true)
+}
+
+// UpsertAll = queue update of all documents matching filter, or insert one if there is none
+func (b *Bulk) UpsertAll(filter *Filter, data interface{}) *Bulk {
+	return b.update(filter, data, true, true)
+}
+
+// replace = queue replace one
+func (b *Bulk) replace(filter *Filter, data interface{}, upsert bool) *Bulk {
+	filterM, err := b.buildFilter(filter)
+
+	if err != nil {
+		return b.fail(len(b.models), err)
+	}
+
+	dataM, err := b.set.buildDocument(data, upsert)
+
+	if err != nil {
+		return b.fail(len(b.models), err)
+	}
+
+	b.models = append(b.models, mongo.NewReplaceOneModel().SetFilter(filterM).SetReplacement(dataM).SetUpsert(upsert))
+
+	return b
+}
+
+// Replace = queue replace of first document matching filter, _id of data is ignored
+func (b *Bulk) Replace(filter *Filter, data interface{}) *Bulk {
+	return b.replace(filter, data, false)
+}
+
+// ReplaceOrInsert = queue replace of first document matching filter, or insert data if there is none
+func (b *Bulk) ReplaceOrInsert(filter *Filter, data interface{}) *Bulk {
+	return b.replace(filter, data, true)
+}
+
+// DeleteOne = queue delete of first document matching filter
+func (b *Bulk) DeleteOne(filter *Filter) *Bulk {
+	filterM, err := b.buildFilter(filter)
+
+	if err != nil {
+		return b.fail(len(b.models), err)
+	}
+
+	b.models = append(b.models, mongo.NewDeleteOneModel().SetFilter(filterM))
+
+	return b
+}
+
+// DeleteAll = queue delete of all documents matching filter
+func (b *Bulk) DeleteAll(filter *Filter) *Bulk {
+	filterM, err := b.buildFilter(filter)
+
+	if err != nil {
+		return b.fail(len(b.models), err)
+	}
+
+	b.models = append(b.models, mongo.NewDeleteManyModel().SetFilter(filterM))
+
+	return b
+}
+
+// Execute = execute queued operations. On write errors the result is still returned with WriteErrors filled
+func (b *Bulk) Execute() (*BulkResult, error) {
+	if b.err != nil {
+		return nil, &CommandError{
+			Message: "Error building bulk write",
+			Kind:    ErrValidation,
+			Err:     b.err,
+		}
+	}
+
+	if len(b.models) == 0 {
+		return nil, validationError("bulk write can't be empty")
+	}
+
+	if b.set.tableName == "" {
+		return nil, validationError("table name not defined")
+	}
+
+	err := b.set.gom.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer b.set.gom.release()
+
+	client := b.set.gom.GetClient()
+
+	collection := client.Database(b.set.gom.GetDatabase()).Collection(b.set.tableName)
+
+	ctx, cancelFunc := b.set.GetContext()
+	defer cancelFunc()
+
+	res, err := collection.BulkWrite(ctx, b.models, options.BulkWrite().SetOrdered(b.ordered))
+
+	result := &BulkResult{
+		UpsertedIDs: map[int64]interface{}{},
+	}
+
+	if res != nil {
+		result.InsertedCount = res.InsertedCount
+		result.MatchedCount = res.MatchedCount
+		result.ModifiedCount = res.ModifiedCount
+		result.DeletedCount = res.DeletedCount
+		result.UpsertedCount = res.UpsertedCount
+
+		for k, v := range res.UpsertedIDs {
+			result.UpsertedIDs[k] = v
+		}
+	}
+
+	if err == nil {
+		return result, nil
+	}
+
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		for _, we := range bwe.WriteErrors {
+			result.WriteErrors = append(result.WriteErrors, BulkWriteError{
+				Index:   we.Index,
+				Code:    we.Code,
+				Message: we.Message,
+			})
+		}
+
+		return result, wrapError("Error executing bulk write", err)
+	}
+
+	return nil, wrapError("Error executing bulk write", err)
+}
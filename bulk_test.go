@@ -0,0 +1,110 @@
+package gom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestBulkModels(t *testing.T) {
+	b := new(Set).Table("hero").Bulk().
+		Insert(&bson.M{"Name": "a"}).
+		UpdateOne(Eq("Name", "a"), NewUpdate().Inc("Age", 1)).
+		UpsertAll(Eq("Name", "b"), &bson.M{"Age": 2}).
+		Replace(Eq("Name", "c"), &bson.M{"_id": 1, "Name": "d"}).
+		DeleteAll(Eq("Name", "e"))
+
+	if b.err != nil {
+		t.Fatalf("unexpected error: %v", b.err)
+	}
+
+	tests := []struct {
+		name  string
+		model mongo.WriteModel
+		want  mongo.WriteModel
+	}{
+		{
+			name:  "insert",
+			model: b.models[0],
+			want:  mongo.NewInsertOneModel().SetDocument(bson.M{"Name": "a"}),
+		},
+		{
+			name:  "update one",
+			model: b.models[1],
+			want:  mongo.NewUpdateOneModel().SetFilter(bson.M{"Name": bson.M{"$eq": "a"}}).SetUpdate(bson.M{"$inc": bson.M{"Age": 1}}).SetUpsert(false),
+		},
+		{
+			name:  "upsert all",
+			model: b.models[2],
+			want:  mongo.NewUpdateManyModel().SetFilter(bson.M{"Name": bson.M{"$eq": "b"}}).SetUpdate(bson.M{"$set": bson.M{"Age": 2}}).SetUpsert(true),
+		},
+		{
+			name:  "replace ignores _id",
+			model: b.models[3],
+			want:  mongo.NewReplaceOneModel().SetFilter(bson.M{"Name": bson.M{"$eq": "c"}}).SetReplacement(bson.M{"Name": "d"}).SetUpsert(false),
+		},
+		{
+			name:  "delete all",
+			model: b.models[4],
+			want:  mongo.NewDeleteManyModel().SetFilter(bson.M{"Name": bson.M{"$eq": "e"}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.model, tt.want) {
+				t.Errorf("got %+v, want %+v", tt.model, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		bulk  *Bulk
+		index int
+		path  string
+	}{
+		{name: "empty", bulk: new(Set).Table("hero").Bulk(), index: -1},
+		{name: "missing table", bulk: new(Set).Bulk().DeleteOne(Eq("a", 1)), index: -1},
+		{name: "nil filter", bulk: new(Set).Table("hero").Bulk().DeleteOne(nil), index: 0},
+		{
+			name:  "invalid filter",
+			bulk:  new(Set).Table("hero").Bulk().Insert(&bson.M{"a": 1}).DeleteAll(And(Eq("a", 1), Eq("", 2))),
+			index: 1,
+			path:  "filter.$and[1]",
+		},
+		{name: "update as replacement", bulk: new(Set).Table("hero").Bulk().Replace(Eq("a", 1), NewUpdate().Set("a", 2)), index: 0},
+		{name: "first error is kept", bulk: new(Set).Table("hero").Bulk().UpdateOne(Eq("a", 1), NewUpdate()).DeleteOne(nil), index: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.bulk.Execute()
+
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("got %v, want validation error", err)
+			}
+
+			var opErr *BulkOperationError
+
+			if errors.As(err, &opErr) != (tt.index >= 0) {
+				t.Fatalf("got %v, want *BulkOperationError only for invalid operation", err)
+			}
+
+			if tt.index >= 0 && opErr.Index != tt.index {
+				t.Errorf("got index %d, want %d", opErr.Index, tt.index)
+			}
+
+			var filterErr *FilterError
+
+			if tt.path != "" && (!errors.As(err, &filterErr) || filterErr.Path != tt.path) {
+				t.Errorf("got %v, want *FilterError at %s", err, tt.path)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package gom
+
+import "context"
+
+// Collection = typed collection of T, built on top of Set and Command
+type Collection[T any] struct {
+	gom       *Gom
+	tableName string
+}
+
+// NewCollection = create typed collection of T for given table/collection name
+func NewCollection[T any](g *Gom, tableName string) *Collection[T] {
+	c := new(Collection[T])
+	c.gom = g
+	c.tableName = tableName
+
+	return c
+}
+
+// Set = get Set of this collection with given context, for sort, skip, limit and other options
+func (c *Collection[T]) Set(ctx context.Context) *Set {
+	return c.gom.Set(nil).Context(ctx).Table(c.tableName)
+}
+
+// Find = get all documents matching filter, nil filter matches all documents
+func (c *Collection[T]) Find(ctx context.Context, filter *Filter) ([]T, error) {
+	res := []T{}
+
+	_, err := c.Set(ctx).Filter(filter).Result(&res).Cmd().Get()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindOne = get first document matching filter, returns ErrNotFound if there is none
+func (c *Collection[T]) FindOne(ctx context.Context, filter *Filter) (*T, error) {
+	res := new(T)
+
+	err := c.Set(ctx).Filter(filter).Result(res).Cmd().GetOne()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Insert = insert one document, returns inserted id
+func (c *Collection[T]) Insert(ctx context.Context, doc *T) (interface{}, error) {
+	return c.Set(ctx).Cmd().Insert(doc)
+}
+
+// Update = update first document matching filter with fields of doc, returns matched count
+func (c *Collection[T]) Update(ctx context.Context, filter *Filter, doc *T) (int64, error) {
+	if filter == nil {
+		return 0, validationError("filter can't be empty")
+	}
+
+	return c.Set(ctx).Filter(filter).Cmd().Update(doc)
+}
+
+// UpdateAll = update all documents matching filter with fields of doc, returns matched count
+func (c *Collection[T]) UpdateAll(ctx context.Context, filter *Filter, doc *T) (int64, error) {
+	if filter == nil {
+		return 0, validationError("filter can't be empty")
+	}
+
+	res, err := c.Set(ctx).Filter(filter).Cmd().UpdateAll(doc)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return res.MatchedCount, nil
+}
+
+// Delete = delete first document matching filter, returns deleted count
+func (c *Collection[T]) Delete(ctx context.Context, filter *Filter) (int64, error) {
+	if filter == nil {
+		return 0, validationError("filter can't be empty")
+	}
+
+	return c.Set(ctx).Filter(filter).Cmd().DeleteOne()
+}
+
+// DeleteAll = delete all documents matching filter, returns deleted count
+func (c *Collection[T]) DeleteAll(ctx context.Context, filter *Filter) (int64, error) {
+	if filter == nil {
+		return 0, validationError("filter can't be empty")
+	}
+
+	return c.Set(ctx).Filter(filter).Cmd().DeleteAll()
+}
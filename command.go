@@ -4,10 +4,11 @@ import (
 	"errors"
 	"reflect"
 	"strings"
+	"time"
 
-	"github.com/eaciit/toolkit"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // Command = command struct
@@ -23,43 +24,76 @@ func newCommand(s *Set) *Command {
 	return c
 }
 
+// acquire = return error of set, eg: invalid filter, or register an in-flight command
+func (c *Command) acquire() error {
+	if c.set.err != nil {
+		return c.set.err
+	}
+
+	return c.set.gom.acquire()
+}
+
+// nearError = error of Near and NearSphere filter, they can't be used by aggregation $match and CountDocuments.
+// Filter is ignored if usePipe and pipe is set
+func (c *Command) nearError(usePipe bool) error {
+	if c.set.nearPath == "" || (usePipe && c.set.pipe != nil) {
+		return nil
+	}
+
+	return filterError(c.set.nearPath, "$near and $nearSphere can't be used by Get, Cursor, Each, Count, Exists and Distinct, use GetOne or PipeGeoNear")
+}
+
 // Pipe = Return Pipe Aggregate
 func (c *Command) Pipe() []bson.M {
 	return c.set.buildPipe()
 }
 
-// Get = get data. it'll use Filter as default. if pipe not null => Filter will be ignored
+// Get = get data. it'll use Filter as default. if pipe not null => Filter will be ignored.
+// It returns estimated count of all documents, inside transaction the exact count is used because estimated count isn't allowed there
 func (c *Command) Get() (int64, error) {
 	tableName := c.set.tableName
 	result := c.set.result
 
 	if result == nil {
-		return 0, errors.New("result argument must be set")
+		return 0, validationError("result argument must be set")
 	}
 
 	resultVal := reflect.ValueOf(result)
 	if resultVal.Kind() != reflect.Ptr && resultVal.Kind() != reflect.Slice {
-		return 0, errors.New("result argument must be a slice")
+		return 0, validationError("result argument must be a slice")
 	}
 
+	err := c.nearError(true)
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer c.set.gom.release()
+
 	client := c.set.gom.GetClient()
 
 	ctx, cancelFunc := c.set.GetContext()
 	defer cancelFunc()
 
 	if tableName == "" {
-		return 0, errors.New("table name not defined")
+		return 0, validationError("table name not defined")
 	}
 
 	collection := client.Database(c.set.gom.GetDatabase()).Collection(tableName)
 
 	var cur *mongo.Cursor
-	var err error
 
 	cur, err = collection.Aggregate(ctx, c.set.buildPipe())
 
 	if err != nil {
-		return 0, errors.New(toolkit.Sprintf("Error finding all documents: %s", err.Error()))
+		return 0, wrapError("Error finding all documents", err)
 	}
 
 	defer cur.Close(ctx)
@@ -67,44 +101,501 @@ func (c *Command) Get() (int64, error) {
 	err = cur.All(ctx, result)
 
 	if err != nil {
-		return 0, errors.New(toolkit.Sprintf("Decode error: %s", err.Error()))
+		return 0, wrapError("Decode error", err)
 	}
 
-	countTotal, _ := collection.EstimatedDocumentCount(ctx)
+	var countTotal int64
+
+	// count command isn't allowed in transaction, CountDocuments runs as aggregation there
+	if c.set.gom.session != nil {
+		countTotal, err = collection.CountDocuments(ctx, bson.M{})
+	} else {
+		countTotal, err = collection.EstimatedDocumentCount(ctx)
+	}
+
+	if err != nil {
+		return 0, wrapError("Error counting all documents", err)
+	}
 
 	return countTotal, nil
 }
 
+// Cursor = open streaming cursor with Filter, or Pipe if set. Timeout applies to opening the cursor and to each fetch of the next batch, not to the whole iteration. Close must be called when done
+func (c *Command) Cursor() (*Cursor, error) {
+	tableName := c.set.tableName
+
+	if tableName == "" {
+		return nil, validationError("table name not defined")
+	}
+
+	err := c.nearError(true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(tableName)
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	opts := options.Aggregate()
+
+	if c.set.batchSize != nil {
+		opts.SetBatchSize(*c.set.batchSize)
+	}
+
+	cur, err := collection.Aggregate(ctx, c.set.buildPipe(), opts)
+
+	if err != nil {
+		c.set.gom.release()
+		return nil, wrapError("Error finding all documents", err)
+	}
+
+	return newCursor(c.set.parentContext(), c.set.contextTimeout*time.Second, cur, c.set.gom.release), nil
+}
+
+// Each = stream documents one by one to fn without loading all of them. Return ErrStop from fn to stop early.
+// Timeout applies like Cursor, time spent in fn isn't limited
+func (c *Command) Each(fn func(decode func(v interface{}) error) error) error {
+	cur, err := c.Cursor()
+
+	if err != nil {
+		return err
+	}
+
+	defer cur.Close()
+
+	for cur.Next() {
+		err = fn(cur.Decode)
+
+		if errors.Is(err, ErrStop) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
+
+// GetWithCount = get data like Get. This returns count of documents matching filter or pipe (skip & limit ignored) and count of all documents
+func (c *Command) GetWithCount() (int64, int64, error) {
+	countAll, err := c.Get()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	countFilter, err := c.Count()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return countFilter, countAll, nil
+}
+
+// Count = count documents matching filter, or output of pipe if set. Skip, limit and sort are ignored
+func (c *Command) Count() (int64, error) {
+	tableName := c.set.tableName
+
+	if tableName == "" {
+		return 0, validationError("table name not defined")
+	}
+
+	err := c.nearError(true)
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(tableName)
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	if c.set.pipe == nil {
+		count, err := collection.CountDocuments(ctx, c.set.filter)
+
+		if err != nil {
+			return 0, wrapError("Error counting documents", err)
+		}
+
+		return count, nil
+	}
+
+	pipe := append([]bson.M{}, c.set.pipe...)
+	pipe = append(pipe, bson.M{
+		"$count": "count",
+	})
+
+	cur, err := collection.Aggregate(ctx, pipe)
+
+	if err != nil {
+		return 0, wrapError("Error counting documents", err)
+	}
+
+	defer cur.Close(ctx)
+
+	res := []struct {
+		Count int64 `bson:"count"`
+	}{}
+
+	err = cur.All(ctx, &res)
+
+	if err != nil {
+		return 0, wrapError("Decode error", err)
+	}
+
+	if len(res) == 0 {
+		return 0, nil
+	}
+
+	return res[0].Count, nil
+}
+
+// Exists = check any document matches filter, pipe ignored
+func (c *Command) Exists() (bool, error) {
+	tableName := c.set.tableName
+
+	if tableName == "" {
+		return false, validationError("table name not defined")
+	}
+
+	err := c.nearError(false)
+
+	if err != nil {
+		return false, err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return false, err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(tableName)
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	count, err := collection.CountDocuments(ctx, c.set.filter, options.Count().SetLimit(1))
+
+	if err != nil {
+		return false, wrapError("Error checking document", err)
+	}
+
+	return count > 0, nil
+}
+
+// Distinct = get distinct values of field from documents matching filter, pipe ignored. Result must be a pointer of slice
+func (c *Command) Distinct(field string, result interface{}) error {
+	tableName := c.set.tableName
+
+	if tableName == "" {
+		return validationError("table name not defined")
+	}
+
+	if field == "" {
+		return validationError("field can't be empty")
+	}
+
+	resultVal := reflect.ValueOf(result)
+
+	if resultVal.Kind() != reflect.Ptr || resultVal.Elem().Kind() != reflect.Slice {
+		return validationError("result argument must be a pointer of slice")
+	}
+
+	err := c.nearError(false)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(tableName)
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	values, err := collection.Distinct(ctx, field, c.set.filter)
+
+	if err != nil {
+		return wrapError("Error finding distinct values", err)
+	}
+
+	// decode through bson so result element type is respected
+	raw, err := bson.Marshal(bson.M{
+		"values": values,
+	})
+
+	if err != nil {
+		return wrapError("Decode error", err)
+	}
+
+	err = bson.Raw(raw).Lookup("values").Unmarshal(result)
+
+	if err != nil {
+		return wrapError("Decode error", err)
+	}
+
+	return nil
+}
+
 // GetOne = get one data. it'll use Filter as default, pipe ignored.
 func (c *Command) GetOne() error {
 	tableName := c.set.tableName
+
+	err := c.validateOneResult()
+
+	if err != nil {
+		return err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(tableName)
+
+	err = collection.FindOne(ctx, c.set.filter).Decode(c.set.result)
+
+	if err != nil {
+		return wrapError("Error finding document", err)
+	}
+
+	return nil
+}
+
+// validateOneResult = check result is set and is a pointer of single document
+func (c *Command) validateOneResult() error {
 	result := c.set.result
 
 	if result == nil {
-		return errors.New("result argument must be set")
+		return validationError("result argument must be set")
 	}
 
 	resultVal := reflect.ValueOf(result)
 
 	if resultVal.Kind() != reflect.Ptr {
-		return errors.New("result argument must be a pointer")
+		return validationError("result argument must be a pointer")
 	}
 
 	if strings.Contains(reflect.TypeOf(result).String(), "[]") {
-		return errors.New("result argument must be a pointer, not a slice")
+		return validationError("result argument must be a pointer, not a slice")
+	}
+
+	return nil
+}
+
+// FindOneAndUpdate = atomically update one data matching filter and decode it into result. Data is struct/map pointer to $set, or *Update
+func (c *Command) FindOneAndUpdate(data interface{}, params *FindAndModifyParams) error {
+	err := c.validateOneResult()
+
+	if err != nil {
+		return err
+	}
+
+	update, updateOpts, err := c.set.buildUpdate(data)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	if len(c.set.filter.(bson.M)) == 0 {
+		return validationError("filter can't be empty")
 	}
 
 	client := c.set.gom.GetClient()
 
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
+
+	opts := options.FindOneAndUpdate()
+
+	if updateOpts.ArrayFilters != nil {
+		opts.SetArrayFilters(*updateOpts.ArrayFilters)
+	}
+
+	if params != nil {
+		opts.SetUpsert(params.Upsert)
+
+		if params.ReturnNew {
+			opts.SetReturnDocument(options.After)
+		}
+	}
+
+	if sort := c.set.buildSort(); sort != nil {
+		opts.SetSort(sort)
+	}
+
+	if c.set.projection != nil {
+		opts.SetProjection(c.set.projection)
+	}
+
 	ctx, cancelFunc := c.set.GetContext()
 	defer cancelFunc()
 
-	collection := client.Database(c.set.gom.GetDatabase()).Collection(tableName)
+	err = collection.FindOneAndUpdate(ctx, c.set.filter, update, opts).Decode(c.set.result)
+
+	if err != nil {
+		return wrapError("Error finding and updating document", err)
+	}
+
+	return nil
+}
+
+// FindOneAndReplace = atomically replace one data matching filter and decode it into result. _id of data is ignored
+func (c *Command) FindOneAndReplace(data interface{}, params *FindAndModifyParams) error {
+	err := c.validateOneResult()
+
+	if err != nil {
+		return err
+	}
+
+	dataM, err := c.set.buildDocument(data, false)
+
+	if err != nil {
+		return err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	if len(c.set.filter.(bson.M)) == 0 {
+		return validationError("filter can't be empty")
+	}
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
+
+	opts := options.FindOneAndReplace()
+
+	if params != nil {
+		opts.SetUpsert(params.Upsert)
+
+		if params.ReturnNew {
+			opts.SetReturnDocument(options.After)
+		}
+	}
+
+	if sort := c.set.buildSort(); sort != nil {
+		opts.SetSort(sort)
+	}
+
+	if c.set.projection != nil {
+		opts.SetProjection(c.set.projection)
+	}
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	err = collection.FindOneAndReplace(ctx, c.set.filter, dataM, opts).Decode(c.set.result)
+
+	if err != nil {
+		return wrapError("Error finding and replacing document", err)
+	}
+
+	return nil
+}
+
+// FindOneAndDelete = atomically delete one data matching filter and decode it into result
+func (c *Command) FindOneAndDelete() error {
+	err := c.validateOneResult()
+
+	if err != nil {
+		return err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	if len(c.set.filter.(bson.M)) == 0 {
+		return validationError("filter can't be empty")
+	}
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
+
+	opts := options.FindOneAndDelete()
+
+	if sort := c.set.buildSort(); sort != nil {
+		opts.SetSort(sort)
+	}
+
+	if c.set.projection != nil {
+		opts.SetProjection(c.set.projection)
+	}
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
 
-	err := collection.FindOne(ctx, c.set.filter).Decode(c.set.result)
+	err = collection.FindOneAndDelete(ctx, c.set.filter, opts).Decode(c.set.result)
 
 	if err != nil {
-		return errors.New(toolkit.Sprintf("Error finding document: %s", err.Error()))
+		return wrapError("Error finding and deleting document", err)
 	}
 
 	return nil
@@ -112,11 +603,19 @@ func (c *Command) GetOne() error {
 
 // Insert = insert one data, for multiple data use InsertAll
 func (c *Command) Insert(data interface{}) (interface{}, error) {
+	err := c.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.set.gom.release()
+
 	client := c.set.gom.GetClient()
 
 	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
 
-	dataM, err := c.set.buildData(data, true)
+	dataM, err := c.set.buildDocument(data, true)
 
 	if err != nil {
 		return nil, err
@@ -128,7 +627,7 @@ func (c *Command) Insert(data interface{}) (interface{}, error) {
 	res, err := collection.InsertOne(ctx, dataM)
 
 	if err != nil {
-		return nil, err
+		return nil, wrapError("Error inserting document", err)
 	}
 
 	id := res.InsertedID
@@ -138,6 +637,14 @@ func (c *Command) Insert(data interface{}) (interface{}, error) {
 
 // InsertAll = insert multiple data
 func (c *Command) InsertAll(data interface{}) ([]interface{}, error) {
+	err := c.acquire()
+
+	if err != nil {
+		return []interface{}{}, err
+	}
+
+	defer c.set.gom.release()
+
 	client := c.set.gom.GetClient()
 
 	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
@@ -154,7 +661,7 @@ func (c *Command) InsertAll(data interface{}) ([]interface{}, error) {
 	res, err := collection.InsertMany(ctx, datas.([]interface{}))
 
 	if err != nil {
-		return []interface{}{}, err
+		return []interface{}{}, wrapError("Error inserting documents", err)
 	}
 
 	ids := res.InsertedIDs
@@ -162,44 +669,160 @@ func (c *Command) InsertAll(data interface{}) ([]interface{}, error) {
 	return ids, nil
 }
 
-// Update = update data with filter or pipe
+// UpdateResult = result of UpdateAll, Upsert and UpsertAll
+type UpdateResult struct {
+	MatchedCount  int64
+	ModifiedCount int64
+	UpsertedCount int64
+	UpsertedID    interface{}
+}
+
+// Update = update data with filter or pipe. Data is struct/map pointer to $set, or *Update for other operators
 func (c *Command) Update(data interface{}) (int64, error) {
+	res, err := c.update(data, false, false)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return res.MatchedCount, nil
+}
+
+// UpdateAll = update all data matching filter
+func (c *Command) UpdateAll(data interface{}) (*UpdateResult, error) {
+	return c.update(data, true, false)
+}
+
+// Upsert = update one data matching filter, or insert it if there is none
+func (c *Command) Upsert(data interface{}) (*UpdateResult, error) {
+	return c.update(data, false, true)
+}
+
+// UpsertAll = update all data matching filter, or insert one if there is none
+func (c *Command) UpsertAll(data interface{}) (*UpdateResult, error) {
+	return c.update(data, true, true)
+}
+
+// update = update one or many data with optional upsert
+func (c *Command) update(data interface{}, many, upsert bool) (*UpdateResult, error) {
+	err := c.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.set.gom.release()
+
 	client := c.set.gom.GetClient()
 
 	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
 
-	dataM, err := c.set.buildData(data, false)
+	update, opts, err := c.set.buildUpdate(data)
 
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	if len(c.set.filter.(bson.M)) == 0 {
-		return 0, errors.New("filter can't be empty")
+		return nil, validationError("filter can't be empty")
 	}
 
+	opts.SetUpsert(upsert)
+
 	ctx, cancelFunc := c.set.GetContext()
 	defer cancelFunc()
 
-	res, err := collection.UpdateOne(ctx, c.set.filter, bson.M{
-		"$set": dataM,
-	})
+	var res *mongo.UpdateResult
+
+	if many {
+		res, err = collection.UpdateMany(ctx, c.set.filter, update, opts)
+	} else {
+		res, err = collection.UpdateOne(ctx, c.set.filter, update, opts)
+	}
 
 	if err != nil {
-		return 0, err
+		if many {
+			return nil, wrapError("Error updating documents", err)
+		}
+
+		return nil, wrapError("Error updating document", err)
 	}
 
-	return res.MatchedCount, nil
+	return &UpdateResult{
+		MatchedCount:  res.MatchedCount,
+		ModifiedCount: res.ModifiedCount,
+		UpsertedCount: res.UpsertedCount,
+		UpsertedID:    res.UpsertedID,
+	}, nil
+}
+
+// Replace = replace whole document matching filter with data, fields missing in data are removed. _id of data is ignored so stored _id stays the same
+func (c *Command) Replace(data interface{}) (*UpdateResult, error) {
+	return c.replace(data, false)
+}
+
+// ReplaceOrInsert = replace whole document matching filter with data, or insert it if there is none. _id of data is kept, it must match _id of the stored document
+func (c *Command) ReplaceOrInsert(data interface{}) (*UpdateResult, error) {
+	return c.replace(data, true)
+}
+
+// replace = replace one data with optional upsert
+func (c *Command) replace(data interface{}, upsert bool) (*UpdateResult, error) {
+	err := c.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
+
+	dataM, err := c.set.buildDocument(data, upsert)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.set.filter.(bson.M)) == 0 {
+		return nil, validationError("filter can't be empty")
+	}
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	res, err := collection.ReplaceOne(ctx, c.set.filter, dataM, options.Replace().SetUpsert(upsert))
+
+	if err != nil {
+		return nil, wrapError("Error replacing document", err)
+	}
+
+	return &UpdateResult{
+		MatchedCount:  res.MatchedCount,
+		ModifiedCount: res.ModifiedCount,
+		UpsertedCount: res.UpsertedCount,
+		UpsertedID:    res.UpsertedID,
+	}, nil
 }
 
 // DeleteOne = delete one data with filter or pipe
 func (c *Command) DeleteOne() (int64, error) {
+	err := c.acquire()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer c.set.gom.release()
+
 	client := c.set.gom.GetClient()
 
 	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
 
 	if len(c.set.filter.(bson.M)) == 0 {
-		return 0, errors.New("filter can't be empty")
+		return 0, validationError("filter can't be empty")
 	}
 
 	ctx, cancelFunc := c.set.GetContext()
@@ -208,7 +831,7 @@ func (c *Command) DeleteOne() (int64, error) {
 	res, err := collection.DeleteOne(ctx, c.set.filter)
 
 	if err != nil {
-		return 0, err
+		return 0, wrapError("Error deleting document", err)
 	}
 
 	return res.DeletedCount, nil
@@ -216,6 +839,14 @@ func (c *Command) DeleteOne() (int64, error) {
 
 // DeleteAll = delete all data with filter or pipe
 func (c *Command) DeleteAll() (int64, error) {
+	err := c.acquire()
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer c.set.gom.release()
+
 	client := c.set.gom.GetClient()
 
 	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
@@ -226,7 +857,7 @@ func (c *Command) DeleteAll() (int64, error) {
 	res, err := collection.DeleteMany(ctx, c.set.filter)
 
 	if err != nil {
-		return 0, err
+		return 0, wrapError("Error deleting documents", err)
 	}
 
 	return res.DeletedCount, nil
@@ -234,6 +865,14 @@ func (c *Command) DeleteAll() (int64, error) {
 
 // Drop = drop table/collection
 func (c *Command) Drop() error {
+	err := c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
 	client := c.set.gom.GetClient()
 
 	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
@@ -241,10 +880,10 @@ func (c *Command) Drop() error {
 	ctx, cancelFunc := c.set.GetContext()
 	defer cancelFunc()
 
-	err := collection.Drop(ctx)
+	err = collection.Drop(ctx)
 
 	if err != nil {
-		return err
+		return wrapError("Error dropping collection", err)
 	}
 
 	return nil
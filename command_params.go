@@ -0,0 +1,42 @@
+package gom
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindAndModifyParams = params model for FindOneAndUpdate and FindOneAndReplace
+type FindAndModifyParams struct {
+	// ReturnNew = decode document after modification, default is before
+	ReturnNew bool
+	// Upsert = insert document if nothing matches filter
+	Upsert bool
+}
+
+// TimeSeriesParams = time-series params of CreateTable
+type TimeSeriesParams struct {
+	TimeField string
+	MetaField string
+	// Granularity = one of seconds, minutes, hours
+	Granularity string
+}
+
+// CreateTableParams = params model for CreateTable
+type CreateTableParams struct {
+	// Capped = create capped table, SizeInBytes is required
+	Capped       bool
+	SizeInBytes  int64
+	MaxDocuments int64
+	TimeSeries   *TimeSeriesParams
+	// ExpireAfterSeconds = TTL of documents, for time-series or clustered table
+	ExpireAfterSeconds *int64
+	Collation          *options.Collation
+	// ClusteredIndex = cluster documents by _id
+	ClusteredIndex bool
+	// Validator = document validator, eg: from Validator(model)
+	Validator bson.M
+	// ValidationLevel = one of ValidationLevelStrict, ValidationLevelModerate, ValidationLevelOff
+	ValidationLevel string
+	// ValidationAction = one of ValidationActionError, ValidationActionWarn
+	ValidationAction string
+}
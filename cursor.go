@@ -0,0 +1,99 @@
+package gom
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Cursor = streaming cursor over Command result, Close must be called when done.
+// Timeout applies to each fetch of the next batch, not to the whole iteration. Parent context still stops the iteration
+type Cursor struct {
+	cur     *mongo.Cursor
+	parent  context.Context
+	timeout time.Duration
+	release func()
+	err     error
+	closed  bool
+}
+
+// newCursor = create new cursor
+func newCursor(parent context.Context, timeout time.Duration, cur *mongo.Cursor, release func()) *Cursor {
+	c := new(Cursor)
+	c.cur = cur
+	c.parent = parent
+	c.timeout = timeout
+	c.release = release
+
+	return c
+}
+
+// getContext = context of one cursor operation, limited by timeout
+func (c *Cursor) getContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.parent, c.timeout)
+}
+
+// Next = move to next document, returns false when exhausted, failed or context is done. Check Err after
+func (c *Cursor) Next() bool {
+	if c.closed || c.err != nil {
+		return false
+	}
+
+	ctx, cancelFunc := c.getContext()
+	defer cancelFunc()
+
+	if c.cur.Next(ctx) {
+		return true
+	}
+
+	err := c.cur.Err()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		c.err = wrapError("Error iterating documents", err)
+	}
+
+	return false
+}
+
+// Decode = decode current document into v
+func (c *Cursor) Decode(v interface{}) error {
+	err := c.cur.Decode(v)
+
+	if err != nil {
+		return wrapError("Decode error", err)
+	}
+
+	return nil
+}
+
+// Err = get last error of iteration
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Close = close cursor and release gom
+func (c *Cursor) Close() error {
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+
+	defer c.release()
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), c.timeout)
+	defer cancelFunc()
+
+	err := c.cur.Close(ctx)
+
+	if err != nil {
+		return wrapError("Error closing cursor", err)
+	}
+
+	return nil
+}
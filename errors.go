@@ -0,0 +1,250 @@
+package gom
+
+import (
+	"context"
+	"errors"
+
+	"github.com/eaciit/toolkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// codeDocumentValidationFailure = server error code when a write is rejected by collection validator
+const codeDocumentValidationFailure = 121
+
+var (
+	// ErrNotConnected = returned when a command runs before Init/Connect succeeded
+	ErrNotConnected = errors.New("gom is not connected, call Init or Connect first")
+	// ErrClosed = returned when a command runs after Close/Drain has been called
+	ErrClosed = errors.New("gom connection is closed")
+	// ErrAlreadyConnected = returned when Init/Connect is called on a gom that isn't closed with Close
+	ErrAlreadyConnected = errors.New("gom is already connected, call Close first")
+	// ErrInTransaction = returned when calling an operation that isn't allowed on gom of transaction
+	ErrInTransaction = errors.New("operation is not allowed inside transaction")
+	// ErrNotFound = no document matches the filter
+	ErrNotFound = errors.New("document not found")
+	// ErrDuplicateKey = write violates a unique index, use errors.As with *DuplicateKeyError to get the key
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrTimeout = command exceeded Timeout or the parent context deadline
+	ErrTimeout = errors.New("operation timed out")
+	// ErrValidation = invalid command arguments or document rejected by collection validator
+	ErrValidation = errors.New("validation failed")
+	// ErrStop = return it from Each callback to stop iteration without error
+	ErrStop = errors.New("stop iteration")
+)
+
+// ConfigError = error caused by invalid Config
+type ConfigError struct {
+	Err error
+}
+
+// Error = implement error interface
+func (e *ConfigError) Error() string {
+	return toolkit.Sprintf("Config error: %s", e.Err.Error())
+}
+
+// Unwrap = return original error
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConnectionError = error caused by creating, connecting or disconnecting the client
+type ConnectionError struct {
+	Err error
+}
+
+// Error = implement error interface
+func (e *ConnectionError) Error() string {
+	return toolkit.Sprintf("Connection error: %s", e.Err.Error())
+}
+
+// Unwrap = return original error
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// CommandError = error returned by Command methods. Kind is one of ErrNotFound, ErrTimeout, ErrValidation or nil
+type CommandError struct {
+	Message string
+	Kind    error
+	Err     error
+}
+
+// Error = implement error interface
+func (e *CommandError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return toolkit.Sprintf("%s: %s", e.Message, e.Err.Error())
+}
+
+// Is = match the error kind, so errors.Is(err, gom.ErrNotFound) works
+func (e *CommandError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// Unwrap = return original driver error
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// DuplicateKeyError = error returned when a write violates a unique index
+type DuplicateKeyError struct {
+	Message    string
+	KeyPattern bson.M
+	KeyValue   bson.M
+	Err        error
+}
+
+// Error = implement error interface
+func (e *DuplicateKeyError) Error() string {
+	return toolkit.Sprintf("%s: %s", e.Message, e.Err.Error())
+}
+
+// Is = match ErrDuplicateKey
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+// Unwrap = return original driver error
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.Err
+}
+
+// FilterError = invalid filter, Path is location of the invalid sub filter, eg: filter.$and[1].$not
+type FilterError struct {
+	Path    string
+	Message string
+}
+
+// Error = implement error interface
+func (e *FilterError) Error() string {
+	return toolkit.Sprintf("Invalid filter at %s: %s", e.Path, e.Message)
+}
+
+// Is = match ErrValidation
+func (e *FilterError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// BulkOperationError = invalid operation queued in Bulk, Index is position of the operation in queue. Err is the original error, eg: *FilterError
+type BulkOperationError struct {
+	Index int
+	Err   error
+}
+
+// Error = implement error interface
+func (e *BulkOperationError) Error() string {
+	return toolkit.Sprintf("Invalid bulk operation at index %d: %s", e.Index, e.Err.Error())
+}
+
+// Unwrap = return original error
+func (e *BulkOperationError) Unwrap() error {
+	return e.Err
+}
+
+// filterError = create filter error at path
+func filterError(path, message string) error {
+	return &FilterError{
+		Path:    path,
+		Message: message,
+	}
+}
+
+// validationError = create command error with ErrValidation kind
+func validationError(message string) error {
+	return &CommandError{
+		Message: message,
+		Kind:    ErrValidation,
+	}
+}
+
+// wrapError = classify driver error and wrap it with message, nil stays nil
+func wrapError(message string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if mongo.IsDuplicateKeyError(err) {
+		dupErr := &DuplicateKeyError{
+			Message: message,
+			Err:     err,
+		}
+
+		raw := duplicateKeyRaw(err)
+
+		if raw != nil {
+			pattern, ok := raw.Lookup("keyPattern").DocumentOK()
+			if ok {
+				bson.Unmarshal(pattern, &dupErr.KeyPattern)
+			}
+
+			value, ok := raw.Lookup("keyValue").DocumentOK()
+			if ok {
+				bson.Unmarshal(value, &dupErr.KeyValue)
+			}
+		}
+
+		return dupErr
+	}
+
+	cmdErr := &CommandError{
+		Message: message,
+		Err:     err,
+	}
+
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		cmdErr.Kind = ErrNotFound
+	case mongo.IsTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		cmdErr.Kind = ErrTimeout
+	case hasErrorCode(err, codeDocumentValidationFailure):
+		cmdErr.Kind = ErrValidation
+	}
+
+	return cmdErr
+}
+
+// duplicateKeyRaw = get raw server error of the first duplicate key error
+func duplicateKeyRaw(err error) bson.Raw {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if isDuplicateKeyCode(e.Code) {
+				return e.Raw
+			}
+		}
+	}
+
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) {
+		for _, e := range bwe.WriteErrors {
+			if isDuplicateKeyCode(e.Code) {
+				return e.Raw
+			}
+		}
+	}
+
+	var ce mongo.CommandError
+	if errors.As(err, &ce) {
+		return ce.Raw
+	}
+
+	return nil
+}
+
+// isDuplicateKeyCode = check server error code is duplicate key
+func isDuplicateKeyCode(code int) bool {
+	return code == 11000 || code == 11001 || code == 12582
+}
+
+// hasErrorCode = check server error code inside driver error
+func hasErrorCode(err error, code int) bool {
+	var se mongo.ServerError
+	if errors.As(err, &se) {
+		return se.HasErrorCode(code)
+	}
+
+	return false
+}
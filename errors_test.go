@@ -0,0 +1,88 @@
+package gom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestWrapError(t *testing.T) {
+	raw, err := bson.Marshal(bson.M{
+		"keyPattern": bson.M{"Email": int32(1)},
+		"keyValue":   bson.M{"Email": "a@b.c"},
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dupWrite := mongo.WriteError{Code: 11000, Message: "E11000 duplicate key", Raw: raw}
+	validationWrite := mongo.WriteError{Code: 121, Message: "Document failed validation"}
+
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{name: "duplicate key of write", err: mongo.WriteException{WriteErrors: mongo.WriteErrors{dupWrite}}, kind: ErrDuplicateKey},
+		{name: "duplicate key of bulk write", err: mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: dupWrite}}}, kind: ErrDuplicateKey},
+		{name: "no documents", err: mongo.ErrNoDocuments, kind: ErrNotFound},
+		{name: "wrapped no documents", err: fmt.Errorf("find: %w", mongo.ErrNoDocuments), kind: ErrNotFound},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, kind: ErrTimeout},
+		{name: "validation failure of write", err: mongo.WriteException{WriteErrors: mongo.WriteErrors{validationWrite}}, kind: ErrValidation},
+		{name: "validation failure of command", err: mongo.CommandError{Code: 121, Message: "Document failed validation"}, kind: ErrValidation},
+		{name: "other", err: errors.New("network error"), kind: nil},
+	}
+
+	kinds := []error{ErrDuplicateKey, ErrNotFound, ErrTimeout, ErrValidation}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapError("Error", tt.err)
+
+			for _, kind := range kinds {
+				if errors.Is(got, kind) != (kind == tt.kind) {
+					t.Errorf("errors.Is(%v, %v) = %v", got, kind, !(kind == tt.kind))
+				}
+			}
+
+			// driver exceptions aren't comparable, errors.Is only matches comparable errors so errors.As is checked too
+			if reflect.TypeOf(tt.err).Comparable() && !errors.Is(got, tt.err) {
+				t.Errorf("errors.Is doesn't reach original error from %v", got)
+			}
+
+			original := reflect.New(reflect.TypeOf(tt.err))
+
+			if !errors.As(got, original.Interface()) || !reflect.DeepEqual(original.Elem().Interface(), tt.err) {
+				t.Errorf("errors.As doesn't reach original error from %v", got)
+			}
+
+			if tt.kind != ErrDuplicateKey {
+				return
+			}
+
+			var dupErr *DuplicateKeyError
+
+			if !errors.As(got, &dupErr) {
+				t.Fatalf("got %T, want *DuplicateKeyError", got)
+			}
+
+			if want := (bson.M{"Email": int32(1)}); !reflect.DeepEqual(dupErr.KeyPattern, want) {
+				t.Errorf("got key pattern %v, want %v", dupErr.KeyPattern, want)
+			}
+
+			if want := (bson.M{"Email": "a@b.c"}); !reflect.DeepEqual(dupErr.KeyValue, want) {
+				t.Errorf("got key value %v, want %v", dupErr.KeyValue, want)
+			}
+		})
+	}
+
+	if wrapError("Error", nil) != nil {
+		t.Errorf("nil error must stay nil")
+	}
+}
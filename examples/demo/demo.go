@@ -142,6 +142,82 @@ func (d *Demo) UpdateMap(g *gom.Gom) {
 	}
 }
 
+// UpdateOperators = example update with update operators
+func (d *Demo) UpdateOperators(g *gom.Gom) {
+	toolkit.Println("===== Update With Operators =====")
+	update := gom.NewUpdate().Inc("Age", 1).Set("RealName", "Bruce Thomas Wayne").CurrentDate("UpdatedAt", "date")
+
+	var err error
+	if d.useParams {
+		_, err = g.Set(&gom.SetParams{
+			TableName: "hero",
+			Filter:    gom.Eq("Name", "Batman"),
+			Timeout:   10,
+		}).Cmd().Update(update)
+	} else {
+		_, err = g.Set(nil).Table("hero").Timeout(10).Filter(gom.Eq("Name", "Batman")).Cmd().Update(update)
+	}
+
+	if err != nil {
+		toolkit.Println(err.Error())
+		return
+	}
+}
+
+// UpdateAll = example update all data matching filter
+func (d *Demo) UpdateAll(g *gom.Gom) {
+	toolkit.Println("===== Update All =====")
+	update := gom.NewUpdate().Inc("Age", 1)
+
+	var res *gom.UpdateResult
+	var err error
+	if d.useParams {
+		res, err = g.Set(&gom.SetParams{
+			TableName: "hero",
+			Filter:    gom.EndWith("Name", "man"),
+			Timeout:   10,
+		}).Cmd().UpdateAll(update)
+	} else {
+		res, err = g.Set(nil).Table("hero").Timeout(10).Filter(gom.EndWith("Name", "man")).Cmd().UpdateAll(update)
+	}
+
+	if err != nil {
+		toolkit.Println(err.Error())
+		return
+	}
+
+	toolkit.Println("Matched", res.MatchedCount, "Modified", res.ModifiedCount)
+}
+
+// Upsert = example update data or insert it if not exists
+func (d *Demo) Upsert(g *gom.Gom) {
+	toolkit.Println("===== Upsert =====")
+	hero := bson.M{
+		"Name":     "Cyborg",
+		"RealName": "Victor Stone",
+		"Age":      25,
+	}
+
+	var res *gom.UpdateResult
+	var err error
+	if d.useParams {
+		res, err = g.Set(&gom.SetParams{
+			TableName: "hero",
+			Filter:    gom.Eq("Name", "Cyborg"),
+			Timeout:   10,
+		}).Cmd().Upsert(&hero)
+	} else {
+		res, err = g.Set(nil).Table("hero").Timeout(10).Filter(gom.Eq("Name", "Cyborg")).Cmd().Upsert(&hero)
+	}
+
+	if err != nil {
+		toolkit.Println(err.Error())
+		return
+	}
+
+	toolkit.Println("Matched", res.MatchedCount, "Upserted", res.UpsertedID)
+}
+
 // DeleteOne = example delete one data
 func (d *Demo) DeleteOne(g *gom.Gom) {
 	toolkit.Println("===== Delete One =====")
@@ -312,6 +388,78 @@ func (d *Demo) GetAllWithSkipLimit(g *gom.Gom) int64 {
 	return int64(len(res))
 }
 
+// GetAllWithCount = example get data with filter, skip and limit, and count of filtered and all data
+func (d *Demo) GetAllWithCount(g *gom.Gom) int64 {
+	toolkit.Println("===== Get All With Count =====")
+	res := []models.Hero{}
+
+	var cFilter, cTotal int64
+	var err error
+	if d.useParams {
+		cFilter, cTotal, err = g.Set(&gom.SetParams{
+			TableName: "hero",
+			Result:    &res,
+			Filter:    gom.Gt("Age", 30),
+			Timeout:   10,
+			Skip:      1,
+			Limit:     2,
+		}).Cmd().GetWithCount()
+	} else {
+		cFilter, cTotal, err = g.Set(nil).Timeout(10).Table("hero").Filter(gom.Gt("Age", 30)).Skip(1).Limit(2).Result(&res).Cmd().GetWithCount()
+	}
+
+	if err != nil {
+		toolkit.Println(err.Error())
+		return 0
+	}
+
+	toolkit.Println(len(res), "of", cFilter, "filtered of", cTotal)
+
+	for _, h := range res {
+		toolkit.Println(h)
+	}
+
+	return int64(len(res))
+}
+
+// Each = example stream data one by one without loading all of them
+func (d *Demo) Each(g *gom.Gom) {
+	toolkit.Println("===== Each =====")
+
+	fn := func(decode func(v interface{}) error) error {
+		h := models.Hero{}
+
+		err := decode(&h)
+
+		if err != nil {
+			return err
+		}
+
+		toolkit.Println(h)
+
+		if h.Name == "Batman" {
+			return gom.ErrStop
+		}
+
+		return nil
+	}
+
+	var err error
+	if d.useParams {
+		err = g.Set(&gom.SetParams{
+			TableName: "hero",
+			BatchSize: 2,
+			Timeout:   10,
+		}).Cmd().Each(fn)
+	} else {
+		err = g.Set(nil).Table("hero").Timeout(10).BatchSize(2).Cmd().Each(fn)
+	}
+
+	if err != nil {
+		toolkit.Println(err.Error())
+	}
+}
+
 // GetOne = example get one data without filter or pipe
 func (d *Demo) GetOne(g *gom.Gom) {
 	toolkit.Println("===== Get One =====")
@@ -716,6 +864,77 @@ func (d *Demo) FilterExists(g *gom.Gom) {
 	}
 }
 
+// Count = example count data with filter
+func (d *Demo) Count(g *gom.Gom) {
+	toolkit.Println("===== Count =====")
+
+	var count int64
+	var err error
+	if d.useParams {
+		count, err = g.Set(&gom.SetParams{
+			TableName: "hero",
+			Filter:    gom.Gt("Age", 30),
+			Timeout:   10,
+		}).Cmd().Count()
+	} else {
+		count, err = g.Set(nil).Table("hero").Timeout(10).Filter(gom.Gt("Age", 30)).Cmd().Count()
+	}
+
+	if err != nil {
+		toolkit.Println(err.Error())
+		return
+	}
+
+	toolkit.Println("Age > 30:", count)
+}
+
+// Exists = example check data exists with filter
+func (d *Demo) Exists(g *gom.Gom) {
+	toolkit.Println("===== Exists Command =====")
+
+	var exists bool
+	var err error
+	if d.useParams {
+		exists, err = g.Set(&gom.SetParams{
+			TableName: "hero",
+			Filter:    gom.Eq("Name", "Batman"),
+			Timeout:   10,
+		}).Cmd().Exists()
+	} else {
+		exists, err = g.Set(nil).Table("hero").Timeout(10).Filter(gom.Eq("Name", "Batman")).Cmd().Exists()
+	}
+
+	if err != nil {
+		toolkit.Println(err.Error())
+		return
+	}
+
+	toolkit.Println("Batman exists:", exists)
+}
+
+// Distinct = example get distinct values of field
+func (d *Demo) Distinct(g *gom.Gom) {
+	toolkit.Println("===== Distinct =====")
+	ages := []int{}
+
+	var err error
+	if d.useParams {
+		err = g.Set(&gom.SetParams{
+			TableName: "hero",
+			Timeout:   10,
+		}).Cmd().Distinct("Age", &ages)
+	} else {
+		err = g.Set(nil).Table("hero").Timeout(10).Cmd().Distinct("Age", &ages)
+	}
+
+	if err != nil {
+		toolkit.Println(err.Error())
+		return
+	}
+
+	toolkit.Println(ages)
+}
+
 // GetByPipe = example get all data pipe
 func (d *Demo) GetByPipe(g *gom.Gom) {
 	toolkit.Println("===== Get By Pipe =====")
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+
 	"github.com/ariefsn/gom/examples/demo"
+	"github.com/ariefsn/gom/examples/models"
 	"github.com/eaciit/toolkit"
 
 	"github.com/ariefsn/gom"
@@ -18,15 +21,29 @@ func main() {
 		Database: "test",
 	}
 
-	g.Init(cfg)
+	err := g.Init(cfg)
 
-	err := g.CheckClient()
+	if err != nil {
+		toolkit.Println(toolkit.Sprintf("Init Error: %s", err.Error()))
+		return
+	}
+
+	defer g.Close(context.Background())
+
+	err = g.CheckClient()
 
 	if err != nil {
 		toolkit.Println(toolkit.Sprintf("Connection Error: %s", err.Error()))
 		return
 	}
 
+	err = g.EnsureIndexes(context.Background(), &models.Hero{})
+
+	if err != nil {
+		toolkit.Println(toolkit.Sprintf("Ensure Indexes Error: %s", err.Error()))
+		return
+	}
+
 	d := demo.NewDemo()
 	// false => chaining set
 	// true => use SetParams
@@ -41,6 +58,11 @@ func main() {
 		d.GetAll(g)
 		d.UpdateMap(g)
 		d.GetAll(g)
+		d.UpdateOperators(g)
+		d.GetAll(g)
+		d.UpdateAll(g)
+		d.Upsert(g)
+		d.GetAll(g)
 		d.DeleteOne(g)
 		d.GetAll(g)
 	}
@@ -49,6 +71,8 @@ func main() {
 	d.GetAllWithSkip(g)
 	d.GetAllWithLimit(g)
 	d.GetAllWithSkipLimit(g)
+	d.GetAllWithCount(g)
+	d.Each(g)
 	d.GetOne(g)
 	d.FilterEq(g)
 	d.FilterNe(g)
@@ -64,6 +88,9 @@ func main() {
 	d.FilterNin(g)
 	d.FilterExists(g)
 	d.GetByPipe(g)
+	d.Count(g)
+	d.Exists(g)
+	d.Distinct(g)
 	d.FilterAnd(g)
 	d.FilterOr(g)
 	d.Sort(g, "asc")
@@ -7,9 +7,14 @@ import (
 // Hero struct
 type Hero struct {
 	ID       primitive.ObjectID `json:"_id" bson:"_id"`
-	Name     string             `json:"Name" bson:"Name"`
+	Name     string             `json:"Name" bson:"Name" gom:"index"`
 	RealName string             `json:"RealName" bson:"RealName"`
-	Age      int                `json:"Age" bson:"Age"`
+	Age      int                `json:"Age" bson:"Age" gom:"index,desc,min=0"`
+}
+
+// TableName = table name of hero, used by EnsureIndexes
+func (h *Hero) TableName() string {
+	return "hero"
 }
 
 type Herox struct {
@@ -1,11 +1,15 @@
 package gom
 
 import (
-	"fmt"
+	"errors"
+	"reflect"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/eaciit/toolkit"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // FilterOp is string represent enumeration of supported filter command
@@ -18,6 +22,8 @@ const (
 	OpOr = "$or"
 	// OpNot is Not
 	OpNot = "$not"
+	// OpNor is NOR
+	OpNor = "$nor"
 	// OpEq is Equal
 	OpEq = "$eq"
 	// OpNe is Not Equal
@@ -38,6 +44,8 @@ const (
 	OpStartWith = "$startwith"
 	// OpEndWith is End with
 	OpEndWith = "$endwith"
+	// OpRegex is raw Regex
+	OpRegex = "$regex"
 	// OpIn is In
 	OpIn = "$in"
 	// OpNin is Not in
@@ -54,14 +62,81 @@ const (
 	OpRangeEq = "rangeEq"
 	// ElemMatch is Elem Match operator
 	OpElemMatch = "$elemMatch"
+	// OpAll is array contains all values
+	OpAll = "$all"
+	// OpSize is array length
+	OpSize = "$size"
+	// OpType is BSON type of field
+	OpType = "$type"
+	// OpMod is modulo
+	OpMod = "$mod"
+	// OpBitsAllSet is all bit positions are set
+	OpBitsAllSet = "$bitsAllSet"
+	// OpBitsAnySet is any bit position is set
+	OpBitsAnySet = "$bitsAnySet"
+	// OpBitsAllClear is all bit positions are clear
+	OpBitsAllClear = "$bitsAllClear"
+	// OpBitsAnyClear is any bit position is clear
+	OpBitsAnyClear = "$bitsAnyClear"
 )
 
+// BsonType is BSON type alias used by Type filter
+type BsonType string
+
+const (
+	// TypeDouble is 64-bit floating point
+	TypeDouble BsonType = "double"
+	// TypeString is string
+	TypeString BsonType = "string"
+	// TypeObject is embedded document
+	TypeObject BsonType = "object"
+	// TypeArray is array
+	TypeArray BsonType = "array"
+	// TypeBinData is binary data
+	TypeBinData BsonType = "binData"
+	// TypeObjectID is ObjectId
+	TypeObjectID BsonType = "objectId"
+	// TypeBool is boolean
+	TypeBool BsonType = "bool"
+	// TypeDate is date
+	TypeDate BsonType = "date"
+	// TypeNull is null
+	TypeNull BsonType = "null"
+	// TypeRegex is regular expression
+	TypeRegex BsonType = "regex"
+	// TypeJavascript is JavaScript code
+	TypeJavascript BsonType = "javascript"
+	// TypeInt is 32-bit integer
+	TypeInt BsonType = "int"
+	// TypeTimestamp is timestamp
+	TypeTimestamp BsonType = "timestamp"
+	// TypeLong is 64-bit integer
+	TypeLong BsonType = "long"
+	// TypeDecimal is Decimal128
+	TypeDecimal BsonType = "decimal"
+	// TypeMinKey is min key
+	TypeMinKey BsonType = "minKey"
+	// TypeMaxKey is max key
+	TypeMaxKey BsonType = "maxKey"
+	// TypeNumber is any of double, int, long and decimal
+	TypeNumber BsonType = "number"
+)
+
+// bsonTypes = supported BsonType aliases
+var bsonTypes = map[BsonType]bool{
+	TypeDouble: true, TypeString: true, TypeObject: true, TypeArray: true, TypeBinData: true, TypeObjectID: true,
+	TypeBool: true, TypeDate: true, TypeNull: true, TypeRegex: true, TypeJavascript: true, TypeInt: true,
+	TypeTimestamp: true, TypeLong: true, TypeDecimal: true, TypeMinKey: true, TypeMaxKey: true, TypeNumber: true,
+}
+
 // Filter holding Items, Field, Operation, and Value
 type Filter struct {
 	Items []*Filter
 	Field string
 	Op    FilterOp
 	Value interface{}
+	// CaseSensitive = StartWith, EndWith and Contains match case, default is case insensitive
+	CaseSensitive bool
 }
 
 // newFilter create new filter with given parameter
@@ -86,7 +161,12 @@ func Or(items ...*Filter) *Filter {
 	return newFilter("", OpOr, nil, items)
 }
 
-// Sort create new filter with Sort operation
+// Nor create new filter with Nor operation, match documents that fail all items
+func Nor(items ...*Filter) *Filter {
+	return newFilter("", OpNor, nil, items)
+}
+
+// Sort create new filter with Sort operation. It isn't a query filter, Compile rejects it, use Set Sort
 func Sort(field string, sortType string) *Filter {
 	sort := -1
 
@@ -102,7 +182,7 @@ func Eq(field string, v interface{}) *Filter {
 	return newFilter(field, OpEq, v, nil)
 }
 
-// Not create new filter with Not operation
+// Not create new filter with Not operation, negate single field filter with $not or other filters with $nor
 func Not(item *Filter) *Filter {
 	return newFilter("", OpNot, nil, []*Filter{item})
 }
@@ -178,7 +258,13 @@ func Nin(field string, ninValues ...interface{}) *Filter {
 	return f
 }
 
-// Contains create new filter with Contains operation
+// MatchCase = make StartWith, EndWith and Contains case sensitive. Case sensitive StartWith can use index
+func (f *Filter) MatchCase() *Filter {
+	f.CaseSensitive = true
+	return f
+}
+
+// Contains create new filter with Contains operation, values are matched literally
 func Contains(field string, values ...string) *Filter {
 	f := new(Filter)
 	f.Field = field
@@ -187,7 +273,7 @@ func Contains(field string, values ...string) *Filter {
 	return f
 }
 
-// StartWith create new filter with StartWith operation
+// StartWith create new filter with StartWith operation, value is matched literally
 func StartWith(field string, value string) *Filter {
 	f := new(Filter)
 	f.Field = field
@@ -196,7 +282,7 @@ func StartWith(field string, value string) *Filter {
 	return f
 }
 
-// EndWith create new filter with EndWith operation
+// EndWith create new filter with EndWith operation, value is matched literally
 func EndWith(field string, value string) *Filter {
 	f := new(Filter)
 	f.Field = field
@@ -205,6 +291,20 @@ func EndWith(field string, value string) *Filter {
 	return f
 }
 
+// Prefix create new filter with case sensitive StartWith operation, anchored regex of it can use index of field
+func Prefix(field string, value string) *Filter {
+	return StartWith(field, value).MatchCase()
+}
+
+// Regex create new filter with raw regex pattern, it isn't escaped. Options are i, m, s, x and u
+func Regex(field, pattern, options string) *Filter {
+	f := new(Filter)
+	f.Field = field
+	f.Op = OpRegex
+	f.Value = primitive.Regex{Pattern: pattern, Options: options}
+	return f
+}
+
 // Exists match the documents that contain the field
 func Exists(field string, value bool) *Filter {
 	f := new(Filter)
@@ -223,132 +323,481 @@ func ElemMatch(field string, filter *Filter) *Filter {
 	return f
 }
 
-// BuildFilter = Build gom filter
+// All create new filter with All operation, array field contains all values
+func All(field string, values ...interface{}) *Filter {
+	return newFilter(field, OpAll, values, nil)
+}
+
+// Size create new filter with Size operation, array field has exactly size elements
+func Size(field string, size int) *Filter {
+	return newFilter(field, OpSize, size, nil)
+}
+
+// Type create new filter with Type operation, field is any of given BSON types
+func Type(field string, types ...BsonType) *Filter {
+	return newFilter(field, OpType, types, nil)
+}
+
+// Mod create new filter with Mod operation, field % divisor == remainder
+func Mod(field string, divisor, remainder int64) *Filter {
+	return newFilter(field, OpMod, []int64{divisor, remainder}, nil)
+}
+
+// BitsAllSet create new filter with BitsAllSet operation. Mask is non negative integer bitmask, []int of bit positions or primitive.Binary
+func BitsAllSet(field string, mask interface{}) *Filter {
+	return newFilter(field, OpBitsAllSet, mask, nil)
+}
+
+// BitsAnySet create new filter with BitsAnySet operation, see BitsAllSet for mask
+func BitsAnySet(field string, mask interface{}) *Filter {
+	return newFilter(field, OpBitsAnySet, mask, nil)
+}
+
+// BitsAllClear create new filter with BitsAllClear operation, see BitsAllSet for mask
+func BitsAllClear(field string, mask interface{}) *Filter {
+	return newFilter(field, OpBitsAllClear, mask, nil)
+}
+
+// BitsAnyClear create new filter with BitsAnyClear operation, see BitsAllSet for mask
+func BitsAnyClear(field string, mask interface{}) *Filter {
+	return newFilter(field, OpBitsAnyClear, mask, nil)
+}
+
+// IsNull match the documents where field exists and is null
+func IsNull(field string) *Filter {
+	return Type(field, TypeNull)
+}
+
+// IsMissing match the documents that don't contain the field
+func IsMissing(field string) *Filter {
+	return Exists(field, false)
+}
+
+// IsNullOrMissing match the documents where field is null or doesn't exist
+func IsNullOrMissing(field string) *Filter {
+	return Eq(field, nil)
+}
+
+// BuildFilter = Build gom filter, returns nil if filter is invalid.
+//
+// Deprecated: use Compile, it returns the error of invalid filter
 func BuildFilter(filter *Filter) bson.M {
+	main, err := Compile(filter)
+
+	if err != nil {
+		return nil
+	}
+
+	return main
+}
+
+// Compile = build gom filter and validate it. It returns *FilterError with path of the invalid sub filter, eg: filter.$and[1].$not
+func Compile(filter *Filter) (bson.M, error) {
+	return compileFilter(filter, "filter")
+}
+
+// compileFilter = validate and build filter at path
+func compileFilter(filter *Filter, path string) (bson.M, error) {
+	if filter == nil {
+		return nil, filterError(path, "filter can't be nil")
+	}
+
 	main := bson.M{}
 	inside := bson.M{}
 
 	switch filter.Op {
-	case OpAnd, OpOr:
+	case OpAnd, OpOr, OpNor, OpNot:
+	default:
+		if filter.Field == "" {
+			return nil, filterError(path, toolkit.Sprintf("field of %s can't be empty", filter.Op))
+		}
+	}
+
+	switch filter.Op {
+	case OpAnd, OpOr, OpNor:
+		if len(filter.Items) == 0 {
+			return nil, filterError(path, toolkit.Sprintf("%s requires at least one filter", filter.Op))
+		}
+
 		insideArr := []interface{}{}
 
-		for _, fi := range filter.Items {
-			fRes := BuildFilter(fi)
+		for idx, fi := range filter.Items {
+			fRes, err := compileFilter(fi, toolkit.Sprintf("%s.%s[%d]", path, filter.Op, idx))
+
+			if err != nil {
+				return nil, err
+			}
+
 			insideArr = append(insideArr, fRes)
 		}
 
 		main[string(filter.Op)] = insideArr
 
-	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNin, OpSort, OpExists:
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte:
 		inside[string(filter.Op)] = filter.Value
 		main[filter.Field] = inside
 
-	// case OpSort:
-	// 	inside.Set(string(filter.Op), filter.Value)
-	// 	main.Set(filter.Field, inside)
+	case OpIn, OpNin, OpAll:
+		rv := reflect.ValueOf(filter.Value)
 
-	case OpBetween, OpRange:
-		switch filter.Value.([]interface{})[0].(type) {
-		case int:
-			gt := 0
-			lt := 0
+		if !rv.IsValid() || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires list of values", filter.Op, filter.Field))
+		}
 
-			if filter.Value != nil {
-				gt = filter.Value.([]interface{})[0].(int)
-				lt = filter.Value.([]interface{})[1].(int)
-			}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			inside[string(filter.Op)] = []interface{}{}
+		} else {
+			inside[string(filter.Op)] = filter.Value
+		}
 
-			main[filter.Field] = bson.M{
-				"$gt": gt,
-				"$lt": lt,
-			}
-		case time.Time:
-			gt := time.Now()
-			lt := time.Now()
+		main[filter.Field] = inside
 
-			if filter.Value != nil {
-				gt = filter.Value.([]interface{})[0].(time.Time)
-				lt = filter.Value.([]interface{})[1].(time.Time)
-			}
+	case OpExists:
+		if _, ok := filter.Value.(bool); !ok {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires bool value", filter.Op, filter.Field))
+		}
 
-			main[filter.Field] = bson.M{
-				"$gt": gt,
-				"$lt": lt,
-			}
+		inside[string(filter.Op)] = filter.Value
+		main[filter.Field] = inside
+
+	case OpSort:
+		return nil, filterError(path, toolkit.Sprintf("sort of %s can't be used as filter, use Set Sort", filter.Field))
+
+	case OpSize:
+		size, ok := nonNegativeInt(filter.Value)
+
+		if !ok {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires non negative integer", filter.Op, filter.Field))
 		}
 
-	case OpBetweenEq, OpRangeEq:
-		switch filter.Value.([]interface{})[0].(type) {
-		case int:
-			gt := 0
-			lt := 0
+		inside[string(filter.Op)] = size
+		main[filter.Field] = inside
 
-			if filter.Value != nil {
-				gt = filter.Value.([]interface{})[0].(int)
-				lt = filter.Value.([]interface{})[1].(int)
-			}
+	case OpType:
+		types, ok := filter.Value.([]BsonType)
 
-			main[filter.Field] = bson.M{
-				"$gte": gt,
-				"$lte": lt,
-			}
-		case time.Time:
-			gt := time.Now()
-			lt := time.Now()
+		if !ok || len(types) == 0 {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires at least one BSON type", filter.Op, filter.Field))
+		}
 
-			if filter.Value != nil {
-				gt = filter.Value.([]interface{})[0].(time.Time)
-				lt = filter.Value.([]interface{})[1].(time.Time)
+		for _, t := range types {
+			if !bsonTypes[t] {
+				return nil, filterError(path, toolkit.Sprintf("unknown BSON type of %s: %s", filter.Field, t))
 			}
+		}
+
+		if len(types) == 1 {
+			inside[string(filter.Op)] = types[0]
+		} else {
+			inside[string(filter.Op)] = types
+		}
 
+		main[filter.Field] = inside
+
+	case OpMod:
+		values, ok := filter.Value.([]int64)
+
+		if !ok || len(values) != 2 {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires divisor and remainder", filter.Op, filter.Field))
+		}
+
+		if values[0] == 0 {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s divisor can't be zero", filter.Op, filter.Field))
+		}
+
+		inside[string(filter.Op)] = values
+		main[filter.Field] = inside
+
+	case OpBitsAllSet, OpBitsAnySet, OpBitsAllClear, OpBitsAnyClear:
+		mask, ok := bitsMask(filter.Value)
+
+		if !ok {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires non negative integer, []int of bit positions or primitive.Binary", filter.Op, filter.Field))
+		}
+
+		inside[string(filter.Op)] = mask
+		main[filter.Field] = inside
+
+	case OpBetween, OpRange, OpBetweenEq, OpRangeEq:
+		from, to, err := rangeValues(filter)
+
+		if err != nil {
+			return nil, filterError(path, err.Error())
+		}
+
+		if filter.Op == OpBetweenEq || filter.Op == OpRangeEq {
 			main[filter.Field] = bson.M{
-				"$gte": gt,
-				"$lte": lt,
+				"$gte": from,
+				"$lte": to,
+			}
+		} else {
+			main[filter.Field] = bson.M{
+				"$gt": from,
+				"$lt": to,
 			}
 		}
 
 	case OpStartWith:
-		main[filter.Field] = bson.M{
-			"$regex":   fmt.Sprintf("^%s.*$", filter.Value),
-			"$options": "i",
+		value, ok := filter.Value.(string)
+
+		if !ok {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires string value", filter.Op, filter.Field))
 		}
 
+		main[filter.Field] = regexCond("^"+regexp.QuoteMeta(value), filter.CaseSensitive)
+
 	case OpEndWith:
-		main[filter.Field] = bson.M{
-			"$regex":   fmt.Sprintf("^.*%s$", filter.Value),
-			"$options": "i",
+		value, ok := filter.Value.(string)
+
+		if !ok {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires string value", filter.Op, filter.Field))
 		}
 
+		main[filter.Field] = regexCond(regexp.QuoteMeta(value)+"$", filter.CaseSensitive)
+
 	case OpContains:
-		if len(filter.Value.([]string)) > 1 {
+		values, ok := filter.Value.([]string)
+
+		if !ok || len(values) == 0 {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires at least one string value", filter.Op, filter.Field))
+		}
+
+		if len(values) > 1 {
 			bfs := []interface{}{}
-			for _, ff := range filter.Value.([]string) {
+			for _, ff := range values {
 				pfm := bson.M{}
-				pfm[filter.Field] = bson.M{
-					"$regex":   fmt.Sprintf(".*%s.*", ff),
-					"$options": "i",
-				}
+				pfm[filter.Field] = regexCond(regexp.QuoteMeta(ff), filter.CaseSensitive)
 
 				bfs = append(bfs, pfm)
 			}
 			main["$or"] = bfs
 		} else {
-			main[filter.Field] = bson.M{
-				"$regex":   fmt.Sprintf(".*%s.*", filter.Value.([]string)[0]),
-				"$options": "i",
-			}
+			main[filter.Field] = regexCond(regexp.QuoteMeta(values[0]), filter.CaseSensitive)
+		}
+
+	case OpRegex:
+		value, ok := filter.Value.(primitive.Regex)
+
+		if !ok {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires primitive.Regex value", filter.Op, filter.Field))
+		}
+
+		if strings.Trim(value.Options, "imsxu") != "" {
+			return nil, filterError(path, toolkit.Sprintf("invalid regex options of %s: %s", filter.Field, value.Options))
+		}
+
+		cond := bson.M{
+			"$regex": value.Pattern,
+		}
+
+		if value.Options != "" {
+			cond["$options"] = value.Options
 		}
 
+		main[filter.Field] = cond
+
 	case OpNot:
-		// field := filter.Items[0].Field
-		// main.Set(field, toolkit.M{}.Set("$not", filter.Items[0].Field))
-		// toolkit.Println(toolkit.JsonStringIndent(main, "\n"))
+		if len(filter.Items) != 1 {
+			return nil, filterError(path, "$not requires exactly one filter")
+		}
+
+		return negateFilter(filter.Items[0], path+".$not")
 
 	case OpElemMatch:
-		inside[string(filter.Op)] = BuildFilter(filter.Value.(*Filter))
+		sub, ok := filter.Value.(*Filter)
+
+		if !ok {
+			return nil, filterError(path, toolkit.Sprintf("%s of %s requires filter value", filter.Op, filter.Field))
+		}
+
+		elem, err := compileFilter(sub, toolkit.Sprintf("%s.%s(%s)", path, filter.Op, filter.Field))
+
+		if err != nil {
+			return nil, err
+		}
+
+		inside[string(filter.Op)] = elem
 		main[filter.Field] = inside
 
+	case OpNear, OpNearSphere, OpGeoWithin, OpGeoIntersects:
+		cond, err := compileGeo(filter)
+
+		if err != nil {
+			return nil, filterError(path, err.Error())
+		}
+
+		main[filter.Field] = cond
+
+	default:
+		return nil, filterError(path, toolkit.Sprintf("unsupported operator %s", filter.Op))
 	}
 
-	return main
+	return main, nil
+}
+
+// nonNegativeInt = get integer value as int64, false if it isn't non negative integer
+func nonNegativeInt(v interface{}) (int64, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), rv.Int() >= 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), rv.Uint() <= uint64(1<<63-1)
+	}
+
+	return 0, false
+}
+
+// bitsMask = check mask of bitwise filter
+func bitsMask(v interface{}) (interface{}, bool) {
+	switch mask := v.(type) {
+	case []int:
+		for _, pos := range mask {
+			if pos < 0 {
+				return nil, false
+			}
+		}
+
+		return mask, true
+	case primitive.Binary:
+		return mask, true
+	}
+
+	return nonNegativeInt(v)
+}
+
+// regexCond = build $regex condition, case insensitive unless caseSensitive
+func regexCond(pattern string, caseSensitive bool) bson.M {
+	cond := bson.M{
+		"$regex": pattern,
+	}
+
+	if !caseSensitive {
+		cond["$options"] = "i"
+	}
+
+	return cond
+}
+
+// rangeValues = get and check both values of range and between filters
+func rangeValues(filter *Filter) (interface{}, interface{}, error) {
+	values, ok := filter.Value.([]interface{})
+
+	if !ok || len(values) != 2 {
+		return nil, nil, errors.New(toolkit.Sprintf("%s of %s requires from and to values", filter.Op, filter.Field))
+	}
+
+	from, to := values[0], values[1]
+	fromKind, toKind := rangeKind(from), rangeKind(to)
+
+	if fromKind == "" || toKind == "" {
+		return nil, nil, errors.New(toolkit.Sprintf("%s of %s supports numbers, Decimal128, string, time and ObjectID, got %T and %T", filter.Op, filter.Field, from, to))
+	}
+
+	if fromKind != toKind {
+		return nil, nil, errors.New(toolkit.Sprintf("%s of %s requires values of the same type, got %T and %T", filter.Op, filter.Field, from, to))
+	}
+
+	return from, to, nil
+}
+
+// rangeKind = comparison group of range value, empty if not supported
+func rangeKind(v interface{}) string {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, primitive.Decimal128:
+		return "number"
+	case string:
+		return "string"
+	case time.Time, primitive.DateTime:
+		return "date"
+	case primitive.ObjectID:
+		return "objectId"
+	}
+
+	return ""
+}
+
+// negateFilter = build negation of filter. Single field filter is negated with field level $not, logical and multi field filters with $nor
+func negateFilter(filter *Filter, path string) (bson.M, error) {
+	if filter == nil {
+		return nil, filterError(path, "filter can't be nil")
+	}
+
+	switch filter.Op {
+	case OpSort:
+		return nil, filterError(path, toolkit.Sprintf("sort of %s can't be negated", filter.Field))
+
+	case OpNear, OpNearSphere:
+		return nil, filterError(path, toolkit.Sprintf("%s of %s can't be negated", filter.Op, filter.Field))
+
+	case OpNot:
+		if len(filter.Items) != 1 {
+			return nil, filterError(path, "$not requires exactly one filter")
+		}
+
+		return compileFilter(filter.Items[0], path+".$not")
+
+	case OpOr, OpNor:
+		negated := *filter
+		negated.Op = OpNor
+
+		if filter.Op == OpNor {
+			negated.Op = OpOr
+		}
+
+		return compileFilter(&negated, path)
+	}
+
+	main, err := compileFilter(filter, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(main) == 1 {
+		for field, v := range main {
+			cond, ok := v.(bson.M)
+
+			if strings.HasPrefix(field, "$") || !ok || !isOperatorDoc(cond) {
+				break
+			}
+
+			// $not accepts regex object on every server version, $regex operator only from 4.0.7
+			if pattern, ok := cond["$regex"].(string); ok && len(cond) <= 2 {
+				options, _ := cond["$options"].(string)
+
+				return bson.M{
+					field: bson.M{
+						"$not": primitive.Regex{Pattern: pattern, Options: options},
+					},
+				}, nil
+			}
+
+			return bson.M{
+				field: bson.M{
+					"$not": cond,
+				},
+			}, nil
+		}
+	}
+
+	return bson.M{
+		"$nor": []interface{}{main},
+	}, nil
+}
+
+// isOperatorDoc = check all keys of document are operators
+func isOperatorDoc(m bson.M) bool {
+	if len(m) == 0 {
+		return false
+	}
+
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+
+	return true
 }
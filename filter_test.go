@@ -0,0 +1,312 @@
+package gom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   bson.M
+	}{
+		{
+			name:   "and",
+			filter: And(Eq("a", 1), Gt("b", 2)),
+			want:   bson.M{"$and": []interface{}{bson.M{"a": bson.M{"$eq": 1}}, bson.M{"b": bson.M{"$gt": 2}}}},
+		},
+		{
+			name:   "range",
+			filter: Range("a", 1, 5),
+			want:   bson.M{"a": bson.M{"$gt": 1, "$lt": 5}},
+		},
+		{
+			name:   "range equal",
+			filter: RangeEq("a", "a", "c"),
+			want:   bson.M{"a": bson.M{"$gte": "a", "$lte": "c"}},
+		},
+		{
+			name:   "nil in values",
+			filter: In("a"),
+			want:   bson.M{"a": bson.M{"$in": []interface{}{}}},
+		},
+		{
+			name:   "start with escapes value",
+			filter: StartWith("a", "a.b*"),
+			want:   bson.M{"a": bson.M{"$regex": `^a\.b\*`, "$options": "i"}},
+		},
+		{
+			name:   "end with escapes value",
+			filter: EndWith("a", "(x)"),
+			want:   bson.M{"a": bson.M{"$regex": `\(x\)$`, "$options": "i"}},
+		},
+		{
+			name:   "contains escapes value",
+			filter: Contains("a", "1+1"),
+			want:   bson.M{"a": bson.M{"$regex": `1\+1`, "$options": "i"}},
+		},
+		{
+			name:   "contains multiple values",
+			filter: Contains("a", "x", "y"),
+			want: bson.M{"$or": []interface{}{
+				bson.M{"a": bson.M{"$regex": "x", "$options": "i"}},
+				bson.M{"a": bson.M{"$regex": "y", "$options": "i"}},
+			}},
+		},
+		{
+			name:   "match case",
+			filter: Contains("a", "X").MatchCase(),
+			want:   bson.M{"a": bson.M{"$regex": "X"}},
+		},
+		{
+			name:   "prefix is case sensitive",
+			filter: Prefix("a", "[x]"),
+			want:   bson.M{"a": bson.M{"$regex": `^\[x\]`}},
+		},
+		{
+			name:   "raw regex isn't escaped",
+			filter: Regex("a", "^x.*", "im"),
+			want:   bson.M{"a": bson.M{"$regex": "^x.*", "$options": "im"}},
+		},
+		{
+			name:   "single type",
+			filter: Type("a", TypeString),
+			want:   bson.M{"a": bson.M{"$type": TypeString}},
+		},
+		{
+			name:   "multiple types",
+			filter: Type("a", TypeInt, TypeLong),
+			want:   bson.M{"a": bson.M{"$type": []BsonType{TypeInt, TypeLong}}},
+		},
+		{
+			name:   "mod",
+			filter: Mod("a", 3, 1),
+			want:   bson.M{"a": bson.M{"$mod": []int64{3, 1}}},
+		},
+		{
+			name:   "bits integer mask",
+			filter: BitsAllSet("a", 5),
+			want:   bson.M{"a": bson.M{"$bitsAllSet": int64(5)}},
+		},
+		{
+			name:   "bits positions",
+			filter: BitsAnyClear("a", []int{0, 3}),
+			want:   bson.M{"a": bson.M{"$bitsAnyClear": []int{0, 3}}},
+		},
+		{
+			name:   "size",
+			filter: Size("a", 2),
+			want:   bson.M{"a": bson.M{"$size": int64(2)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compile(tt.filter)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileNot(t *testing.T) {
+	polygon := NewPolygon(Ring(NewPoint(0, 0), NewPoint(1, 0), NewPoint(1, 1)))
+	line := NewLineString(NewPoint(0, 0), NewPoint(1, 1))
+
+	not := func(field string, cond interface{}) bson.M {
+		return bson.M{field: bson.M{"$not": cond}}
+	}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   bson.M
+	}{
+		{name: "eq", filter: Not(Eq("a", 1)), want: not("a", bson.M{"$eq": 1})},
+		{name: "ne", filter: Not(Ne("a", 1)), want: not("a", bson.M{"$ne": 1})},
+		{name: "gt", filter: Not(Gt("a", 1)), want: not("a", bson.M{"$gt": 1})},
+		{name: "gte", filter: Not(Gte("a", 1)), want: not("a", bson.M{"$gte": 1})},
+		{name: "lt", filter: Not(Lt("a", 1)), want: not("a", bson.M{"$lt": 1})},
+		{name: "lte", filter: Not(Lte("a", 1)), want: not("a", bson.M{"$lte": 1})},
+		{name: "in", filter: Not(In("a", 1, 2)), want: not("a", bson.M{"$in": []interface{}{1, 2}})},
+		{name: "nin", filter: Not(Nin("a", 1)), want: not("a", bson.M{"$nin": []interface{}{1}})},
+		{name: "all", filter: Not(All("a", 1)), want: not("a", bson.M{"$all": []interface{}{1}})},
+		{name: "exists", filter: Not(Exists("a", true)), want: not("a", bson.M{"$exists": true})},
+		{name: "size", filter: Not(Size("a", 1)), want: not("a", bson.M{"$size": int64(1)})},
+		{name: "type", filter: Not(Type("a", TypeNull)), want: not("a", bson.M{"$type": TypeNull})},
+		{name: "mod", filter: Not(Mod("a", 2, 0)), want: not("a", bson.M{"$mod": []int64{2, 0}})},
+		{name: "bits", filter: Not(BitsAnySet("a", 1)), want: not("a", bson.M{"$bitsAnySet": int64(1)})},
+		{name: "range", filter: Not(Range("a", 1, 5)), want: not("a", bson.M{"$gt": 1, "$lt": 5})},
+		{name: "between equal", filter: Not(BetweenEq("a", 1, 5)), want: not("a", bson.M{"$gte": 1, "$lte": 5})},
+		{name: "start with", filter: Not(StartWith("a", "x.")), want: not("a", primitive.Regex{Pattern: `^x\.`, Options: "i"})},
+		{name: "end with", filter: Not(EndWith("a", "x").MatchCase()), want: not("a", primitive.Regex{Pattern: "x$"})},
+		{name: "contains", filter: Not(Contains("a", "x")), want: not("a", primitive.Regex{Pattern: "x", Options: "i"})},
+		{name: "regex", filter: Not(Regex("a", "^x", "m")), want: not("a", primitive.Regex{Pattern: "^x", Options: "m"})},
+		{name: "elem match", filter: Not(ElemMatch("a", Eq("b", 1))), want: not("a", bson.M{"$elemMatch": bson.M{"b": bson.M{"$eq": 1}}})},
+		{name: "geo within", filter: Not(GeoWithin("a", polygon)), want: not("a", bson.M{"$geoWithin": bson.M{"$geometry": polygon}})},
+		{name: "geo intersects", filter: Not(GeoIntersects("a", line)), want: not("a", bson.M{"$geoIntersects": bson.M{"$geometry": line}})},
+		{
+			name:   "contains multiple values",
+			filter: Not(Contains("a", "x", "y")),
+			want: bson.M{"$nor": []interface{}{bson.M{"$or": []interface{}{
+				bson.M{"a": bson.M{"$regex": "x", "$options": "i"}},
+				bson.M{"a": bson.M{"$regex": "y", "$options": "i"}},
+			}}}},
+		},
+		{
+			name:   "and",
+			filter: Not(And(Eq("a", 1), Eq("b", 2))),
+			want:   bson.M{"$nor": []interface{}{bson.M{"$and": []interface{}{bson.M{"a": bson.M{"$eq": 1}}, bson.M{"b": bson.M{"$eq": 2}}}}}},
+		},
+		{
+			name:   "or",
+			filter: Not(Or(Eq("a", 1), Eq("b", 2))),
+			want:   bson.M{"$nor": []interface{}{bson.M{"a": bson.M{"$eq": 1}}, bson.M{"b": bson.M{"$eq": 2}}}},
+		},
+		{
+			name:   "nor",
+			filter: Not(Nor(Eq("a", 1), Eq("b", 2))),
+			want:   bson.M{"$or": []interface{}{bson.M{"a": bson.M{"$eq": 1}}, bson.M{"b": bson.M{"$eq": 2}}}},
+		},
+		{
+			name:   "double not",
+			filter: Not(Not(Eq("a", 1))),
+			want:   bson.M{"a": bson.M{"$eq": 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compile(tt.filter)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *Filter
+		path   string
+	}{
+		{name: "nil", filter: nil, path: "filter"},
+		{name: "empty field", filter: Eq("", 1), path: "filter"},
+		{name: "empty and", filter: And(), path: "filter"},
+		{name: "nil item", filter: Or(Eq("a", 1), nil), path: "filter.$or[1]"},
+		{name: "nested item", filter: And(Eq("a", 1), Or(Eq("", 1))), path: "filter.$and[1].$or[0]"},
+		{name: "elem match", filter: ElemMatch("a", Gt("", 1)), path: "filter.$elemMatch(a)"},
+		{name: "unsupported operator", filter: newFilter("a", "$foo", 1, nil), path: "filter"},
+		{name: "sort", filter: And(Eq("a", 1), Sort("a", "asc")), path: "filter.$and[1]"},
+		{name: "in without list", filter: newFilter("a", OpIn, 1, nil), path: "filter"},
+		{name: "exists without bool", filter: newFilter("a", OpExists, "yes", nil), path: "filter"},
+		{name: "range of different types", filter: Range("a", 1, "5"), path: "filter"},
+		{name: "range of unsupported type", filter: Between("a", true, false), path: "filter"},
+		{name: "start with non string", filter: newFilter("a", OpStartWith, 1, nil), path: "filter"},
+		{name: "contains without values", filter: Contains("a"), path: "filter"},
+		{name: "regex options", filter: Regex("a", "x", "g"), path: "filter"},
+		{name: "size negative", filter: Size("a", -1), path: "filter"},
+		{name: "type empty", filter: Type("a"), path: "filter"},
+		{name: "type unknown", filter: Type("a", "text"), path: "filter"},
+		{name: "mod zero divisor", filter: Mod("a", 0, 1), path: "filter"},
+		{name: "bits negative mask", filter: BitsAllSet("a", -1), path: "filter"},
+		{name: "bits negative position", filter: BitsAllClear("a", []int{1, -1}), path: "filter"},
+		{name: "bits string mask", filter: BitsAnySet("a", "1"), path: "filter"},
+		{name: "not of many", filter: newFilter("", OpNot, nil, []*Filter{Eq("a", 1), Eq("b", 1)}), path: "filter"},
+		{name: "not of nil", filter: Not(nil), path: "filter.$not"},
+		{name: "not of invalid", filter: Not(Eq("", 1)), path: "filter.$not"},
+		{name: "not of sort", filter: Not(Sort("a", "asc")), path: "filter.$not"},
+		{name: "not of near", filter: Not(Near("a", NewPoint(0, 0), 0, 0)), path: "filter.$not"},
+		{name: "not of near sphere", filter: And(Not(NearSphere("a", NewPoint(0, 0), 0, 0))), path: "filter.$and[0].$not"},
+		{name: "double not of invalid", filter: Not(Not(Eq("", 1))), path: "filter.$not.$not"},
+		{name: "invalid point", filter: Near("a", NewPoint(200, 0), 0, 0), path: "filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.filter)
+
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("got %v, want validation error", err)
+			}
+
+			var filterErr *FilterError
+
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("got %T, want *FilterError", err)
+			}
+
+			if filterErr.Path != tt.path {
+				t.Errorf("got path %s, want %s", filterErr.Path, tt.path)
+			}
+		})
+	}
+}
+
+func TestBuildFilterInvalid(t *testing.T) {
+	if got := BuildFilter(Eq("", 1)); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestNearPath(t *testing.T) {
+	near := Near("loc", NewPoint(0, 0), 0, 0)
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   string
+	}{
+		{name: "none", filter: And(Eq("a", 1), GeoWithinBox("loc", NewPoint(0, 0), NewPoint(1, 1))), want: ""},
+		{name: "top level", filter: near, want: "filter"},
+		{name: "inside and", filter: And(Eq("a", 1), near), want: "filter.$and[1]"},
+		{name: "inside nested or", filter: And(Or(Eq("a", 1), NearSphere("loc", NewPoint(0, 0), 0, 0))), want: "filter.$and[0].$or[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nearPath(tt.filter, "filter"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNearRejectedByCommand(t *testing.T) {
+	cmd := newSet(nil, nil).Table("hero").Filter(And(Eq("a", 1), Near("loc", NewPoint(0, 0), 0, 0))).Cmd()
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{name: "exists", run: func() error { _, err := cmd.Exists(); return err }},
+		{name: "distinct", run: func() error { return cmd.Distinct("a", &[]string{}) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filterErr *FilterError
+
+			if err := tt.run(); !errors.As(err, &filterErr) || filterErr.Path != "filter.$and[1]" {
+				t.Errorf("got %v, want *FilterError at filter.$and[1]", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package gom
+
+import (
+	"errors"
+
+	"github.com/eaciit/toolkit"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// OpNear is near point, sorted by distance
+	OpNear FilterOp = "$near"
+	// OpNearSphere is near point on sphere, sorted by distance
+	OpNearSphere FilterOp = "$nearSphere"
+	// OpGeoWithin is within geometry or shape
+	OpGeoWithin FilterOp = "$geoWithin"
+	// OpGeoIntersects is intersects geometry
+	OpGeoIntersects FilterOp = "$geoIntersects"
+)
+
+// Geometry = GeoJSON geometry, one of Point, LineString, Polygon, MultiPolygon
+type Geometry interface {
+	validate() error
+}
+
+// Point = GeoJSON point, coordinates are longitude and latitude
+type Point struct {
+	Type        string    `json:"type" bson:"type"`
+	Coordinates []float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// LineString = GeoJSON line string
+type LineString struct {
+	Type        string      `json:"type" bson:"type"`
+	Coordinates [][]float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// Polygon = GeoJSON polygon, first ring is exterior and the others are holes. Rings must be closed
+type Polygon struct {
+	Type        string        `json:"type" bson:"type"`
+	Coordinates [][][]float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// MultiPolygon = GeoJSON multi polygon
+type MultiPolygon struct {
+	Type        string          `json:"type" bson:"type"`
+	Coordinates [][][][]float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// NewPoint = create GeoJSON point
+func NewPoint(lng, lat float64) Point {
+	return Point{
+		Type:        "Point",
+		Coordinates: []float64{lng, lat},
+	}
+}
+
+// NewLineString = create GeoJSON line string from points
+func NewLineString(points ...Point) LineString {
+	return LineString{
+		Type:        "LineString",
+		Coordinates: pointsCoordinates(points),
+	}
+}
+
+// NewPolygon = create GeoJSON polygon from rings, see Ring
+func NewPolygon(rings ...[][]float64) Polygon {
+	return Polygon{
+		Type:        "Polygon",
+		Coordinates: rings,
+	}
+}
+
+// NewMultiPolygon = create GeoJSON multi polygon
+func NewMultiPolygon(polygons ...Polygon) MultiPolygon {
+	m := MultiPolygon{
+		Type: "MultiPolygon",
+	}
+
+	for _, p := range polygons {
+		m.Coordinates = append(m.Coordinates, p.Coordinates)
+	}
+
+	return m
+}
+
+// Ring = create closed ring of polygon from points, first point is appended if ring isn't closed
+func Ring(points ...Point) [][]float64 {
+	ring := pointsCoordinates(points)
+
+	if len(ring) > 0 && !samePosition(ring[0], ring[len(ring)-1]) {
+		ring = append(ring, ring[0])
+	}
+
+	return ring
+}
+
+// pointsCoordinates = get coordinates of points
+func pointsCoordinates(points []Point) [][]float64 {
+	res := [][]float64{}
+
+	for _, p := range points {
+		res = append(res, p.Coordinates)
+	}
+
+	return res
+}
+
+// samePosition = check positions are equal
+func samePosition(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validatePosition = check position is longitude and latitude in range
+func validatePosition(position []float64) error {
+	if len(position) != 2 {
+		return errors.New("position requires longitude and latitude")
+	}
+
+	if position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+		return errors.New(toolkit.Sprintf("position out of range: %v", position))
+	}
+
+	return nil
+}
+
+// validateRings = check rings of polygon are closed and have at least 4 positions
+func validateRings(rings [][][]float64) error {
+	if len(rings) == 0 {
+		return errors.New("polygon requires at least one ring")
+	}
+
+	for _, ring := range rings {
+		if len(ring) < 4 || !samePosition(ring[0], ring[len(ring)-1]) {
+			return errors.New("polygon ring must be closed and have at least 4 positions")
+		}
+
+		for _, position := range ring {
+			err := validatePosition(position)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate = check point
+func (p Point) validate() error {
+	if p.Type != "Point" {
+		return errors.New("point type must be Point, use NewPoint")
+	}
+
+	return validatePosition(p.Coordinates)
+}
+
+// validate = check line string
+func (l LineString) validate() error {
+	if l.Type != "LineString" {
+		return errors.New("line string type must be LineString, use NewLineString")
+	}
+
+	if len(l.Coordinates) < 2 {
+		return errors.New("line string requires at least 2 positions")
+	}
+
+	for _, position := range l.Coordinates {
+		err := validatePosition(position)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate = check polygon
+func (p Polygon) validate() error {
+	if p.Type != "Polygon" {
+		return errors.New("polygon type must be Polygon, use NewPolygon")
+	}
+
+	return validateRings(p.Coordinates)
+}
+
+// validate = check multi polygon
+func (m MultiPolygon) validate() error {
+	if m.Type != "MultiPolygon" {
+		return errors.New("multi polygon type must be MultiPolygon, use NewMultiPolygon")
+	}
+
+	if len(m.Coordinates) == 0 {
+		return errors.New("multi polygon requires at least one polygon")
+	}
+
+	for _, rings := range m.Coordinates {
+		err := validateRings(rings)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nearValue = value of Near and NearSphere filter
+type nearValue struct {
+	point       Point
+	maxDistance *float64
+	minDistance *float64
+}
+
+// geoShape = legacy shape of GeoWithin filter, eg: $box
+type geoShape struct {
+	op    string
+	value interface{}
+}
+
+// Near create new filter with Near operation, documents are sorted by distance. Distances are in meters, 0 is no limit. Field requires 2dsphere index.
+// Aggregation and count don't support it, so Get, Cursor, Each, Count, Exists and Distinct return *FilterError, use PipeGeoNear instead
+func Near(field string, point Point, maxDistance, minDistance float64) *Filter {
+	return newFilter(field, OpNear, newNearValue(point, maxDistance, minDistance), nil)
+}
+
+// NearSphere create new filter with NearSphere operation, see Near
+func NearSphere(field string, point Point, maxDistance, minDistance float64) *Filter {
+	return newFilter(field, OpNearSphere, newNearValue(point, maxDistance, minDistance), nil)
+}
+
+// newNearValue = create near value, 0 distance is not set
+func newNearValue(point Point, maxDistance, minDistance float64) nearValue {
+	v := nearValue{point: point}
+
+	if maxDistance > 0 {
+		v.maxDistance = &maxDistance
+	}
+
+	if minDistance > 0 {
+		v.minDistance = &minDistance
+	}
+
+	return v
+}
+
+// GeoWithin create new filter with GeoWithin operation, geometry must be Polygon or MultiPolygon
+func GeoWithin(field string, geometry Geometry) *Filter {
+	return newFilter(field, OpGeoWithin, geometry, nil)
+}
+
+// GeoWithinBox create new filter with GeoWithin $box, legacy flat shape
+func GeoWithinBox(field string, bottomLeft, upperRight Point) *Filter {
+	return newFilter(field, OpGeoWithin, geoShape{op: "$box", value: []Point{bottomLeft, upperRight}}, nil)
+}
+
+// GeoWithinCenter create new filter with GeoWithin $center, legacy flat circle with radius in coordinate units
+func GeoWithinCenter(field string, center Point, radius float64) *Filter {
+	return newFilter(field, OpGeoWithin, geoShape{op: "$center", value: []interface{}{center, radius}}, nil)
+}
+
+// GeoWithinCenterSphere create new filter with GeoWithin $centerSphere, spherical circle with radius in radians (meters / 6378100)
+func GeoWithinCenterSphere(field string, center Point, radius float64) *Filter {
+	return newFilter(field, OpGeoWithin, geoShape{op: "$centerSphere", value: []interface{}{center, radius}}, nil)
+}
+
+// GeoWithinPolygon create new filter with GeoWithin $polygon, legacy flat polygon of at least 3 points
+func GeoWithinPolygon(field string, points ...Point) *Filter {
+	return newFilter(field, OpGeoWithin, geoShape{op: "$polygon", value: points}, nil)
+}
+
+// GeoIntersects create new filter with GeoIntersects operation
+func GeoIntersects(field string, geometry Geometry) *Filter {
+	return newFilter(field, OpGeoIntersects, geometry, nil)
+}
+
+// nearPath = path of the first Near or NearSphere sub filter, empty if there is none
+func nearPath(filter *Filter, path string) string {
+	if filter == nil {
+		return ""
+	}
+
+	switch filter.Op {
+	case OpNear, OpNearSphere:
+		return path
+
+	case OpAnd, OpOr, OpNor:
+		for idx, item := range filter.Items {
+			if p := nearPath(item, toolkit.Sprintf("%s.%s[%d]", path, filter.Op, idx)); p != "" {
+				return p
+			}
+		}
+	}
+
+	return ""
+}
+
+// compileGeo = build geo filter condition
+func compileGeo(filter *Filter) (bson.M, error) {
+	switch filter.Op {
+	case OpNear, OpNearSphere:
+		v, ok := filter.Value.(nearValue)
+
+		if !ok {
+			return nil, errors.New(toolkit.Sprintf("%s of %s requires Near value", filter.Op, filter.Field))
+		}
+
+		err := v.point.validate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		near := bson.M{
+			"$geometry": v.point,
+		}
+
+		if v.maxDistance != nil {
+			near["$maxDistance"] = *v.maxDistance
+		}
+
+		if v.minDistance != nil {
+			near["$minDistance"] = *v.minDistance
+		}
+
+		return bson.M{
+			string(filter.Op): near,
+		}, nil
+
+	case OpGeoWithin:
+		if shape, ok := filter.Value.(geoShape); ok {
+			value, err := shape.build()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return bson.M{
+				string(filter.Op): bson.M{
+					shape.op: value,
+				},
+			}, nil
+		}
+
+		switch filter.Value.(type) {
+		case Polygon, MultiPolygon:
+		default:
+			return nil, errors.New(toolkit.Sprintf("%s of %s requires Polygon or MultiPolygon", filter.Op, filter.Field))
+		}
+
+		fallthrough
+
+	case OpGeoIntersects:
+		geometry, ok := filter.Value.(Geometry)
+
+		if !ok {
+			return nil, errors.New(toolkit.Sprintf("%s of %s requires geometry", filter.Op, filter.Field))
+		}
+
+		err := geometry.validate()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return bson.M{
+			string(filter.Op): bson.M{
+				"$geometry": geometry,
+			},
+		}, nil
+	}
+
+	return nil, errors.New(toolkit.Sprintf("unsupported operator %s", filter.Op))
+}
+
+// build = build legacy shape value
+func (s geoShape) build() (interface{}, error) {
+	points := []Point{}
+
+	switch v := s.value.(type) {
+	case []Point:
+		points = v
+	case []interface{}:
+		points = append(points, v[0].(Point))
+	}
+
+	for _, p := range points {
+		if len(p.Coordinates) != 2 {
+			return nil, errors.New(toolkit.Sprintf("%s point requires 2 coordinates", s.op))
+		}
+	}
+
+	switch s.op {
+	case "$box":
+		return [][]float64{points[0].Coordinates, points[1].Coordinates}, nil
+
+	case "$center", "$centerSphere":
+		values := s.value.([]interface{})
+		radius := values[1].(float64)
+
+		if radius <= 0 {
+			return nil, errors.New(toolkit.Sprintf("%s radius must be positive", s.op))
+		}
+
+		return []interface{}{values[0].(Point).Coordinates, radius}, nil
+
+	case "$polygon":
+		if len(points) < 3 {
+			return nil, errors.New("$polygon requires at least 3 points")
+		}
+
+		return pointsCoordinates(points), nil
+	}
+
+	return nil, errors.New(toolkit.Sprintf("unsupported shape %s", s.op))
+}
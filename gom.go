@@ -3,6 +3,7 @@ package gom
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"go.mongodb.org/mongo-driver/mongo"
 
@@ -12,7 +13,13 @@ import (
 
 // Gom struct
 type Gom struct {
-	mongo mongoDB
+	mongo    mongoDB
+	mu       sync.RWMutex
+	closed   bool
+	inFlight sync.WaitGroup
+	parent   *Gom
+	session  mongo.Session
+	txCtx    context.Context
 }
 
 // NewGom = Create new
@@ -20,10 +27,114 @@ func NewGom() *Gom {
 	return new(Gom)
 }
 
-// Init = Init
-func (g *Gom) Init(config Config) {
+// Init = Init with background context, see Connect
+func (g *Gom) Init(config Config) error {
+	return g.Connect(context.Background(), config)
+}
+
+// Connect = Set config and connect the client with given context. It returns *ConfigError or *ConnectionError on failure.
+// It returns ErrAlreadyConnected until Close has disconnected the previous client, Drain isn't enough
+func (g *Gom) Connect(ctx context.Context, config Config) error {
+	if g.parent != nil {
+		return ErrInTransaction
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.mongo.Client != nil {
+		return ErrAlreadyConnected
+	}
+
+	// reset before connecting, so a failed connect after Close returns ErrNotConnected instead of ErrClosed
+	g.closed = false
+
 	g.mongo.SetConfig(config)
-	g.mongo.SetClient()
+
+	return g.mongo.SetClient(ctx)
+}
+
+// Drain = Stop accepting new commands and wait until in-flight commands are done or ctx is done. The client stays connected, call Close to disconnect it
+func (g *Gom) Drain(ctx context.Context) error {
+	if g.parent != nil {
+		return ErrInTransaction
+	}
+
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		g.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close = Drain in-flight commands then disconnect the client. The client is disconnected even if draining timed out
+func (g *Gom) Close(ctx context.Context) error {
+	if g.parent != nil {
+		return ErrInTransaction
+	}
+
+	drainErr := g.Drain(ctx)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.mongo.Client == nil {
+		return drainErr
+	}
+
+	err := g.mongo.Client.Disconnect(ctx)
+
+	g.mongo.Client = nil
+
+	if err != nil {
+		return &ConnectionError{Err: err}
+	}
+
+	return drainErr
+}
+
+// acquire = register an in-flight command, must be followed by release
+func (g *Gom) acquire() error {
+	if g.parent != nil {
+		return g.parent.acquire()
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.closed {
+		return ErrClosed
+	}
+
+	if g.mongo.Client == nil {
+		return ErrNotConnected
+	}
+
+	g.inFlight.Add(1)
+
+	return nil
+}
+
+// release = mark an in-flight command as done
+func (g *Gom) release() {
+	if g.parent != nil {
+		g.parent.release()
+		return
+	}
+
+	g.inFlight.Done()
 }
 
 // Set = Get set query with gom
@@ -35,7 +146,15 @@ func (g *Gom) Set(SetParams *SetParams) *Set {
 
 // CheckClient = Check connection successfull or not
 func (g *Gom) CheckClient() error {
-	err := g.mongo.Client.Ping(context.Background(), readpref.Primary())
+	err := g.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer g.release()
+
+	err = g.mongo.Client.Ping(context.Background(), readpref.Primary())
 
 	if err != nil {
 		return errors.New(toolkit.Sprintf("Couldn't connect to database : %s", err.Error()))
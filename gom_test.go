@@ -0,0 +1,32 @@
+package gom
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestConnectAfterClose(t *testing.T) {
+	ctx := context.Background()
+	g := NewGom()
+
+	err := g.Close(ctx)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := g.CheckClient(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("got %v, want %v", err, ErrClosed)
+	}
+
+	var configErr *ConfigError
+
+	if err := g.Connect(ctx, Config{URI: "http://h1/db"}); !errors.As(err, &configErr) {
+		t.Fatalf("got %v, want *ConfigError", err)
+	}
+
+	if err := g.CheckClient(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("got %v, want %v", err, ErrNotConnected)
+	}
+}
@@ -0,0 +1,563 @@
+package gom
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/eaciit/toolkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// IndexAsc is ascending index
+	IndexAsc = 1
+	// IndexDesc is descending index
+	IndexDesc = -1
+	// IndexText is text index
+	IndexText = "text"
+	// Index2dsphere is 2dsphere index
+	Index2dsphere = "2dsphere"
+	// IndexHashed is hashed index
+	IndexHashed = "hashed"
+)
+
+// IndexKey = field of index with its type, one of IndexAsc, IndexDesc, IndexText, Index2dsphere, IndexHashed
+type IndexKey struct {
+	Field string
+	Type  interface{}
+}
+
+// Index = index definition, Keys are ordered for compound index
+type Index struct {
+	// Name = index name, generated from keys if empty, eg: Name_1_Age_-1
+	Name          string
+	Keys          []IndexKey
+	Unique        bool
+	Sparse        bool
+	PartialFilter *Filter
+	// ExpireAfterSeconds = TTL of document, only for single field date index
+	ExpireAfterSeconds *int32
+	// Weights = field weights of text index
+	Weights bson.M
+}
+
+// IndexInfo = existing index returned by ListIndexes
+type IndexInfo struct {
+	Name                    string `bson:"name"`
+	Keys                    bson.D `bson:"key"`
+	Unique                  bool   `bson:"unique"`
+	Sparse                  bool   `bson:"sparse"`
+	PartialFilterExpression bson.M `bson:"partialFilterExpression"`
+	ExpireAfterSeconds      *int32 `bson:"expireAfterSeconds"`
+	// Weights = field weights of text index, text keys are listed as _fts and _ftsx in Keys
+	Weights bson.M `bson:"weights"`
+}
+
+// Tabler = model with table/collection name, used by EnsureIndexes
+type Tabler interface {
+	TableName() string
+}
+
+// buildKeys = build ordered index keys
+func (i *Index) buildKeys() bson.D {
+	keys := bson.D{}
+
+	for _, k := range i.Keys {
+		keys = append(keys, bson.E{Key: k.Field, Value: k.Type})
+	}
+
+	return keys
+}
+
+// GetName = get index name, generated from keys if Name is empty
+func (i *Index) GetName() string {
+	if i.Name != "" {
+		return i.Name
+	}
+
+	parts := []string{}
+
+	for _, k := range i.Keys {
+		parts = append(parts, k.Field, toolkit.Sprintf("%v", k.Type))
+	}
+
+	return strings.Join(parts, "_")
+}
+
+// validate = check index definition
+func (i *Index) validate() error {
+	if len(i.Keys) == 0 {
+		return validationError("index keys can't be empty")
+	}
+
+	for _, k := range i.Keys {
+		if k.Field == "" {
+			return validationError("index key field can't be empty")
+		}
+
+		switch k.Type {
+		case IndexAsc, IndexDesc, IndexText, Index2dsphere, IndexHashed:
+		default:
+			return validationError(toolkit.Sprintf("invalid index type of %s: %v", k.Field, k.Type))
+		}
+	}
+
+	if i.PartialFilter != nil {
+		_, err := Compile(i.PartialFilter)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexModel = build driver index model
+func (i *Index) indexModel() mongo.IndexModel {
+	opts := options.Index().SetName(i.GetName())
+
+	if i.Unique {
+		opts.SetUnique(true)
+	}
+
+	if i.Sparse {
+		opts.SetSparse(true)
+	}
+
+	if i.PartialFilter != nil {
+		// partial filter is checked by validate
+		partial, _ := Compile(i.PartialFilter)
+		opts.SetPartialFilterExpression(partial)
+	}
+
+	if i.ExpireAfterSeconds != nil {
+		opts.SetExpireAfterSeconds(*i.ExpireAfterSeconds)
+	}
+
+	if i.Weights != nil {
+		opts.SetWeights(i.Weights)
+	}
+
+	return mongo.IndexModel{
+		Keys:    i.buildKeys(),
+		Options: opts,
+	}
+}
+
+// equal = check existing index has same keys and options
+func (i *Index) equal(info IndexInfo) bool {
+	if info.Unique != i.Unique || info.Sparse != i.Sparse || !i.sameKeys(info) {
+		return false
+	}
+
+	if !sameDocument(info.Weights, i.listedWeights()) {
+		return false
+	}
+
+	if (info.ExpireAfterSeconds == nil) != (i.ExpireAfterSeconds == nil) {
+		return false
+	}
+
+	if info.ExpireAfterSeconds != nil && *info.ExpireAfterSeconds != *i.ExpireAfterSeconds {
+		return false
+	}
+
+	if (info.PartialFilterExpression == nil) != (i.PartialFilter == nil) {
+		return false
+	}
+
+	if i.PartialFilter == nil {
+		return true
+	}
+
+	partial, err := Compile(i.PartialFilter)
+
+	return err == nil && sameDocument(info.PartialFilterExpression, partial)
+}
+
+// sameKeys = check existing index has same key spec, name and options are ignored
+func (i *Index) sameKeys(info IndexInfo) bool {
+	keys := i.listedKeys()
+
+	if len(info.Keys) != len(keys) {
+		return false
+	}
+
+	for idx, k := range keys {
+		if info.Keys[idx].Key != k.Key || toolkit.Sprintf("%v", info.Keys[idx].Value) != toolkit.Sprintf("%v", k.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// listedKeys = index keys as listed by server, text keys are replaced by _fts and _ftsx at position of the first text key
+func (i *Index) listedKeys() bson.D {
+	keys := bson.D{}
+	hasText := false
+
+	for _, k := range i.Keys {
+		if k.Type != IndexText {
+			keys = append(keys, bson.E{Key: k.Field, Value: k.Type})
+			continue
+		}
+
+		if !hasText {
+			keys = append(keys, bson.E{Key: "_fts", Value: IndexText}, bson.E{Key: "_ftsx", Value: 1})
+			hasText = true
+		}
+	}
+
+	return keys
+}
+
+// listedWeights = text index weights as listed by server, text fields without weight have weight 1. Nil if index has no text key
+func (i *Index) listedWeights() bson.M {
+	var weights bson.M
+
+	for _, k := range i.Keys {
+		if k.Type != IndexText {
+			continue
+		}
+
+		if weights == nil {
+			weights = bson.M{}
+		}
+
+		weights[k.Field] = 1
+	}
+
+	if weights == nil {
+		return nil
+	}
+
+	for field, weight := range i.Weights {
+		weights[field] = weight
+	}
+
+	return weights
+}
+
+// sameDocument = check documents are equal after BSON round trip, so numeric and nested document types are comparable
+func sameDocument(a, b bson.M) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	na, err := normalizeDocument(a)
+
+	if err != nil {
+		return false
+	}
+
+	nb, err := normalizeDocument(b)
+
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(na, nb)
+}
+
+// normalizeDocument = marshal and unmarshal document
+func normalizeDocument(m bson.M) (bson.M, error) {
+	bs, err := bson.Marshal(m)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res := bson.M{}
+	err = bson.Unmarshal(bs, &res)
+
+	return res, err
+}
+
+// CreateIndex = create index on table, returns index name
+func (c *Command) CreateIndex(index Index) (string, error) {
+	if c.set.tableName == "" {
+		return "", validationError("table name not defined")
+	}
+
+	err := index.validate()
+
+	if err != nil {
+		return "", err
+	}
+
+	err = c.acquire()
+
+	if err != nil {
+		return "", err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	name, err := collection.Indexes().CreateOne(ctx, index.indexModel())
+
+	if err != nil {
+		return "", wrapError("Error creating index", err)
+	}
+
+	return name, nil
+}
+
+// ListIndexes = list indexes of table
+func (c *Command) ListIndexes() ([]IndexInfo, error) {
+	if c.set.tableName == "" {
+		return nil, validationError("table name not defined")
+	}
+
+	err := c.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	cur, err := collection.Indexes().List(ctx)
+
+	if err != nil {
+		return nil, wrapError("Error listing indexes", err)
+	}
+
+	defer cur.Close(ctx)
+
+	res := []IndexInfo{}
+
+	err = cur.All(ctx, &res)
+
+	if err != nil {
+		return nil, wrapError("Decode error", err)
+	}
+
+	return res, nil
+}
+
+// DropIndex = drop index of table by name
+func (c *Command) DropIndex(name string) error {
+	if c.set.tableName == "" {
+		return validationError("table name not defined")
+	}
+
+	if name == "" || name == "_id_" {
+		return validationError("index name can't be empty or _id_")
+	}
+
+	err := c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	collection := client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName)
+
+	ctx, cancelFunc := c.set.GetContext()
+	defer cancelFunc()
+
+	_, err = collection.Indexes().DropOne(ctx, name)
+
+	if err != nil {
+		return wrapError("Error dropping index", err)
+	}
+
+	return nil
+}
+
+// IndexesFromStruct = read index definitions from `gom` struct tag of model fields.
+// Tag options: index, unique, sparse, desc, text, 2dsphere, hashed, ttl=<seconds>, name=<index name>. Schema options of JSONSchema are ignored.
+// Fields with the same name are combined into compound index in field order. Field name is taken from bson tag
+func IndexesFromStruct(model interface{}) ([]Index, error) {
+	rt := reflect.TypeOf(model)
+
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, validationError("model must be a struct")
+	}
+
+	indexes := []Index{}
+	byName := map[string]int{}
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup("gom")
+
+		if !ok {
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+
+		if strings.TrimSpace(opts[0]) != "index" {
+			continue
+		}
+
+		fieldName := bsonFieldName(field)
+		key := IndexKey{Field: fieldName, Type: IndexAsc}
+		index := Index{}
+
+		for _, opt := range opts[1:] {
+			opt = strings.TrimSpace(opt)
+
+			switch {
+			case opt == "unique":
+				index.Unique = true
+			case opt == "sparse":
+				index.Sparse = true
+			case opt == "desc":
+				key.Type = IndexDesc
+			case opt == IndexText, opt == Index2dsphere, opt == IndexHashed:
+				key.Type = opt
+			case strings.HasPrefix(opt, "ttl="):
+				ttl, err := strconv.Atoi(strings.TrimPrefix(opt, "ttl="))
+
+				if err != nil {
+					return nil, validationError(toolkit.Sprintf("invalid ttl of field %s: %s", field.Name, opt))
+				}
+
+				seconds := int32(ttl)
+				index.ExpireAfterSeconds = &seconds
+			case strings.HasPrefix(opt, "name="):
+				index.Name = strings.TrimPrefix(opt, "name=")
+			case opt == "", isSchemaOption(opt):
+			default:
+				return nil, validationError(toolkit.Sprintf("unknown index option of field %s: %s", field.Name, opt))
+			}
+		}
+
+		if index.Name != "" {
+			if pos, ok := byName[index.Name]; ok {
+				existing := &indexes[pos]
+				existing.Keys = append(existing.Keys, key)
+				existing.Unique = existing.Unique || index.Unique
+				existing.Sparse = existing.Sparse || index.Sparse
+				continue
+			}
+
+			byName[index.Name] = len(indexes)
+		}
+
+		index.Keys = []IndexKey{key}
+		indexes = append(indexes, index)
+	}
+
+	return indexes, nil
+}
+
+// bsonFieldName = get stored field name from bson tag, json tag or field name
+func bsonFieldName(field reflect.StructField) string {
+	for _, tagName := range []string{"bson", "json"} {
+		name := strings.Split(field.Tag.Get(tagName), ",")[0]
+
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// EnsureIndexes = create indexes declared with `gom` struct tag of models, see IndexesFromStruct.
+// Existing indexes are matched by name or by key spec. Matching index of the same definition is kept even if its name differs,
+// other matching indexes are dropped and the declared index is created. Undeclared indexes are kept
+func (g *Gom) EnsureIndexes(ctx context.Context, models ...Tabler) error {
+	for _, model := range models {
+		indexes, err := IndexesFromStruct(model)
+
+		if err != nil {
+			return err
+		}
+
+		if len(indexes) == 0 {
+			continue
+		}
+
+		cmd := g.Set(nil).Context(ctx).Table(model.TableName()).Cmd()
+
+		existing, err := cmd.ListIndexes()
+
+		if err != nil {
+			return err
+		}
+
+		drops, creates := planIndexes(indexes, existing)
+
+		for _, name := range drops {
+			err = cmd.DropIndex(name)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, index := range creates {
+			_, err = cmd.CreateIndex(index)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// planIndexes = get names of existing indexes to drop and declared indexes to create. Existing index matches declared index by name or key spec,
+// _id index is never dropped
+func planIndexes(indexes []Index, existing []IndexInfo) ([]string, []Index) {
+	drops := []string{}
+	creates := []Index{}
+	dropped := map[string]bool{}
+
+	for _, index := range indexes {
+		keep := false
+
+		for _, info := range existing {
+			if dropped[info.Name] || (info.Name != index.GetName() && !index.sameKeys(info)) {
+				continue
+			}
+
+			if info.Name == "_id_" || index.equal(info) {
+				keep = true
+				continue
+			}
+
+			drops = append(drops, info.Name)
+			dropped[info.Name] = true
+		}
+
+		if !keep {
+			creates = append(creates, index)
+		}
+	}
+
+	return drops, creates
+}
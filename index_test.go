@@ -0,0 +1,160 @@
+package gom
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIndexEqual(t *testing.T) {
+	textIndex := Index{
+		Keys:    []IndexKey{{Field: "Team", Type: IndexAsc}, {Field: "Title", Type: IndexText}, {Field: "Body", Type: IndexText}},
+		Weights: bson.M{"Title": 10},
+	}
+
+	textKeys := bson.D{{Key: "Team", Value: int32(1)}, {Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}}
+
+	partialIndex := Index{
+		Keys:          []IndexKey{{Field: "Name", Type: IndexAsc}},
+		PartialFilter: And(Gt("Age", 17), Exists("Email", true)),
+	}
+
+	nameKeys := bson.D{{Key: "Name", Value: int32(1)}}
+
+	tests := []struct {
+		name  string
+		index Index
+		info  IndexInfo
+		want  bool
+	}{
+		{
+			name:  "same keys",
+			index: Index{Keys: []IndexKey{{Field: "Name", Type: IndexAsc}}, Unique: true},
+			info:  IndexInfo{Keys: nameKeys, Unique: true},
+			want:  true,
+		},
+		{
+			name:  "different key type",
+			index: Index{Keys: []IndexKey{{Field: "Name", Type: IndexDesc}}},
+			info:  IndexInfo{Keys: nameKeys},
+			want:  false,
+		},
+		{
+			name:  "text index",
+			index: textIndex,
+			info:  IndexInfo{Keys: textKeys, Weights: bson.M{"Title": int32(10), "Body": int32(1)}},
+			want:  true,
+		},
+		{
+			name:  "text index of different weights",
+			index: textIndex,
+			info:  IndexInfo{Keys: textKeys, Weights: bson.M{"Title": int32(5), "Body": int32(1)}},
+			want:  false,
+		},
+		{
+			name:  "text index of different fields",
+			index: textIndex,
+			info:  IndexInfo{Keys: textKeys, Weights: bson.M{"Title": int32(10)}},
+			want:  false,
+		},
+		{
+			name:  "same partial filter",
+			index: partialIndex,
+			info: IndexInfo{Keys: nameKeys, PartialFilterExpression: bson.M{"$and": bson.A{
+				bson.M{"Age": bson.M{"$gt": int32(17)}},
+				bson.M{"Email": bson.M{"$exists": true}},
+			}}},
+			want: true,
+		},
+		{
+			name:  "different partial filter",
+			index: partialIndex,
+			info: IndexInfo{Keys: nameKeys, PartialFilterExpression: bson.M{"$and": bson.A{
+				bson.M{"Age": bson.M{"$gt": int32(20)}},
+				bson.M{"Email": bson.M{"$exists": true}},
+			}}},
+			want: false,
+		},
+		{
+			name:  "missing partial filter",
+			index: partialIndex,
+			info:  IndexInfo{Keys: nameKeys},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.index.equal(tt.info); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanIndexes(t *testing.T) {
+	email := Index{Keys: []IndexKey{{Field: "Email", Type: IndexAsc}}, Unique: true}
+	emailKeys := bson.D{{Key: "Email", Value: int32(1)}}
+
+	tests := []struct {
+		name     string
+		existing []IndexInfo
+		drops    []string
+		creates  int
+	}{
+		{
+			name:     "missing",
+			existing: []IndexInfo{{Name: "_id_", Keys: bson.D{{Key: "_id", Value: int32(1)}}}},
+			drops:    []string{},
+			creates:  1,
+		},
+		{
+			name:     "same name and definition",
+			existing: []IndexInfo{{Name: "Email_1", Keys: emailKeys, Unique: true}},
+			drops:    []string{},
+			creates:  0,
+		},
+		{
+			name:     "same name and different definition",
+			existing: []IndexInfo{{Name: "Email_1", Keys: emailKeys}},
+			drops:    []string{"Email_1"},
+			creates:  1,
+		},
+		{
+			name:     "same definition of other name",
+			existing: []IndexInfo{{Name: "email_unique", Keys: emailKeys, Unique: true}},
+			drops:    []string{},
+			creates:  0,
+		},
+		{
+			name:     "same keys of other name and different options",
+			existing: []IndexInfo{{Name: "email_idx", Keys: emailKeys}},
+			drops:    []string{"email_idx"},
+			creates:  1,
+		},
+		{
+			name: "name of other keys and keys of other name",
+			existing: []IndexInfo{
+				{Name: "Email_1", Keys: bson.D{{Key: "Name", Value: int32(1)}}},
+				{Name: "email_unique", Keys: emailKeys, Unique: true},
+			},
+			drops:   []string{"Email_1"},
+			creates: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drops, creates := planIndexes([]Index{email}, tt.existing)
+
+			if !reflect.DeepEqual(drops, tt.drops) {
+				t.Errorf("got drops %v, want %v", drops, tt.drops)
+			}
+
+			if len(creates) != tt.creates {
+				t.Errorf("got %d creates, want %d", len(creates), tt.creates)
+			}
+		})
+	}
+}
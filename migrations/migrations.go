@@ -0,0 +1,412 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ariefsn/gom"
+	"github.com/eaciit/toolkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// lockID = _id of lock document
+const lockID = "lock"
+
+// codeIllegalOperation = server error code when transactions aren't supported, eg: standalone server
+const codeIllegalOperation = 20
+
+var (
+	// ErrLocked = another process holds the migration lock
+	ErrLocked = errors.New("migrations are locked by another process")
+	// ErrLockLost = lock expired and was taken by another process while migrating, increase LockTTL
+	ErrLockLost = errors.New("migration lock was lost")
+)
+
+// MigrateFunc = migration step, use g for every command so it runs in transaction when enabled
+type MigrateFunc func(ctx context.Context, g *gom.Gom) error
+
+// Migration = versioned migration, versions are applied in ascending order
+type Migration struct {
+	Version     int64
+	Description string
+	Up          MigrateFunc
+	// Down = revert Up, optional but required by Migrator.Down
+	Down MigrateFunc
+}
+
+// Record = applied migration stored in migrations table
+type Record struct {
+	Version     int64     `bson:"_id"`
+	Description string    `bson:"Description"`
+	AppliedAt   time.Time `bson:"AppliedAt"`
+}
+
+// Error = error of failed migration
+type Error struct {
+	// Direction = up or down
+	Direction string
+	Version   int64
+	Err       error
+}
+
+// Error = error message
+func (e *Error) Error() string {
+	return toolkit.Sprintf("migration %d %s failed: %s", e.Version, e.Direction, e.Err.Error())
+}
+
+// Unwrap = underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Params = optional params of Migrator
+type Params struct {
+	// TableName = table of applied migrations, default is migrations
+	TableName string
+	// LockTableName = table of lock document, default is TableName + _lock
+	LockTableName string
+	// LockTTL = lock is considered stale after it, refreshed after every migration. Default is 10 minutes
+	LockTTL time.Duration
+	// Owner = lock owner, default is hostname:pid
+	Owner string
+	// Transaction = run every migration with its record in transaction, falls back to plain run when server doesn't support transactions
+	Transaction bool
+}
+
+// Migrator = run registered migrations through gom
+type Migrator struct {
+	gom        *gom.Gom
+	params     Params
+	migrations []Migration
+}
+
+// New = create migrator, params can be nil
+func New(g *gom.Gom, params *Params) *Migrator {
+	m := new(Migrator)
+	m.gom = g
+
+	if params != nil {
+		m.params = *params
+	}
+
+	if m.params.TableName == "" {
+		m.params.TableName = "migrations"
+	}
+
+	if m.params.LockTableName == "" {
+		m.params.LockTableName = m.params.TableName + "_lock"
+	}
+
+	if m.params.LockTTL <= 0 {
+		m.params.LockTTL = 10 * time.Minute
+	}
+
+	if m.params.Owner == "" {
+		hostname, _ := os.Hostname()
+		m.params.Owner = toolkit.Sprintf("%s:%d", hostname, os.Getpid())
+	}
+
+	return m
+}
+
+// Register = add migrations, version must be positive and unique and Up is required
+func (m *Migrator) Register(migrations ...Migration) error {
+	versions := map[int64]bool{}
+
+	for _, mig := range m.migrations {
+		versions[mig.Version] = true
+	}
+
+	for _, mig := range migrations {
+		if mig.Version <= 0 {
+			return errors.New(toolkit.Sprintf("invalid migration version: %d", mig.Version))
+		}
+
+		if versions[mig.Version] {
+			return errors.New(toolkit.Sprintf("duplicate migration version: %d", mig.Version))
+		}
+
+		if mig.Up == nil {
+			return errors.New(toolkit.Sprintf("migration %d has no Up", mig.Version))
+		}
+
+		versions[mig.Version] = true
+		m.migrations = append(m.migrations, mig)
+	}
+
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+
+	return nil
+}
+
+// Applied = get applied migrations ordered by version
+func (m *Migrator) Applied(ctx context.Context) ([]Record, error) {
+	res := []Record{}
+
+	_, err := m.gom.Set(nil).Context(ctx).Table(m.params.TableName).Sort("_id", "asc").Result(&res).Cmd().Get()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Pending = get registered migrations not applied yet, in the order Up applies them. Nothing is changed, use it for dry-run
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	return m.pending(ctx, 0)
+}
+
+// pending = get not applied migrations up to version, 0 is all
+func (m *Migrator) pending(ctx context.Context, version int64) ([]Migration, error) {
+	applied, err := m.Applied(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return selectPending(m.migrations, applied, version), nil
+}
+
+// selectPending = get registered migrations not in applied records up to version, 0 is all
+func selectPending(migrations []Migration, applied []Record, version int64) []Migration {
+	appliedVersions := map[int64]bool{}
+
+	for _, r := range applied {
+		appliedVersions[r.Version] = true
+	}
+
+	res := []Migration{}
+
+	for _, mig := range migrations {
+		if version > 0 && mig.Version > version {
+			break
+		}
+
+		if !appliedVersions[mig.Version] {
+			res = append(res, mig)
+		}
+	}
+
+	return res
+}
+
+// Up = apply all pending migrations, returns applied migrations. It stops at first failing migration
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.UpTo(ctx, 0)
+}
+
+// UpTo = apply pending migrations up to version, 0 is all. Returns applied migrations
+func (m *Migrator) UpTo(ctx context.Context, version int64) ([]Migration, error) {
+	err := m.lock(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer m.unlock()
+
+	pending, err := m.pending(ctx, version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+
+	for _, mig := range pending {
+		mig := mig
+
+		err = m.run(ctx, func(ctx context.Context, g *gom.Gom) error {
+			err := mig.Up(ctx, g)
+
+			if err != nil {
+				return err
+			}
+
+			_, err = g.Set(nil).Context(ctx).Table(m.params.TableName).Cmd().Insert(&bson.M{
+				"_id":         mig.Version,
+				"Description": mig.Description,
+				"AppliedAt":   time.Now(),
+			})
+
+			return err
+		})
+
+		if err != nil {
+			return done, migrationError("up", mig, err)
+		}
+
+		done = append(done, mig)
+
+		err = m.refreshLock(ctx)
+
+		if err != nil {
+			return done, err
+		}
+	}
+
+	return done, nil
+}
+
+// Down = revert last applied migrations, returns reverted migrations in revert order.
+// Every migration to revert must be registered with Down, it's checked before anything is reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be positive")
+	}
+
+	err := m.lock(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer m.unlock()
+
+	applied, err := m.Applied(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	toRevert, err := selectRevert(m.migrations, applied, steps)
+
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+
+	for _, mig := range toRevert {
+		mig := mig
+
+		err = m.run(ctx, func(ctx context.Context, g *gom.Gom) error {
+			err := mig.Down(ctx, g)
+
+			if err != nil {
+				return err
+			}
+
+			_, err = g.Set(nil).Context(ctx).Table(m.params.TableName).Filter(gom.Eq("_id", mig.Version)).Cmd().DeleteOne()
+
+			return err
+		})
+
+		if err != nil {
+			return done, migrationError("down", mig, err)
+		}
+
+		done = append(done, mig)
+
+		err = m.refreshLock(ctx)
+
+		if err != nil {
+			return done, err
+		}
+	}
+
+	return done, nil
+}
+
+// selectRevert = get registered migrations of last applied records to revert, in revert order. Every one of them must have Down
+func selectRevert(migrations []Migration, applied []Record, steps int) ([]Migration, error) {
+	registered := map[int64]Migration{}
+
+	for _, mig := range migrations {
+		registered[mig.Version] = mig
+	}
+
+	toRevert := []Migration{}
+
+	for i := len(applied) - 1; i >= 0 && len(toRevert) < steps; i-- {
+		mig, ok := registered[applied[i].Version]
+
+		if !ok || mig.Down == nil {
+			return nil, errors.New(toolkit.Sprintf("migration %d can't be reverted, it has no Down", applied[i].Version))
+		}
+
+		toRevert = append(toRevert, mig)
+	}
+
+	return toRevert, nil
+}
+
+// run = run fn in transaction if enabled and supported, or directly with migrator gom
+func (m *Migrator) run(ctx context.Context, fn MigrateFunc) error {
+	if !m.params.Transaction {
+		return fn(ctx, m.gom)
+	}
+
+	err := m.gom.WithTransaction(ctx, func(tx *gom.Gom) error {
+		return fn(ctx, tx)
+	}, nil)
+
+	var serverErr mongo.ServerError
+
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(codeIllegalOperation) {
+		return fn(ctx, m.gom)
+	}
+
+	return err
+}
+
+// lock = take migration lock, returns ErrLocked if another owner holds a not expired lock
+func (m *Migrator) lock(ctx context.Context) error {
+	now := time.Now()
+
+	_, err := m.gom.Set(nil).Context(ctx).Table(m.params.LockTableName).
+		Filter(gom.And(gom.Eq("_id", lockID), gom.Lt("ExpiresAt", now))).
+		Cmd().
+		Upsert(gom.NewUpdate().
+			Set("Owner", m.params.Owner).
+			Set("LockedAt", now).
+			Set("ExpiresAt", now.Add(m.params.LockTTL)))
+
+	if errors.Is(err, gom.ErrDuplicateKey) {
+		return ErrLocked
+	}
+
+	return err
+}
+
+// refreshLock = extend lock expiry, returns ErrLockLost if lock isn't owned anymore
+func (m *Migrator) refreshLock(ctx context.Context) error {
+	matched, err := m.gom.Set(nil).Context(ctx).Table(m.params.LockTableName).
+		Filter(gom.And(gom.Eq("_id", lockID), gom.Eq("Owner", m.params.Owner))).
+		Cmd().
+		Update(gom.NewUpdate().Set("ExpiresAt", time.Now().Add(m.params.LockTTL)))
+
+	if err != nil {
+		return err
+	}
+
+	if matched == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// unlock = release lock if still owned, with background context so canceled ctx still releases it
+func (m *Migrator) unlock() {
+	m.gom.Set(nil).Table(m.params.LockTableName).
+		Filter(gom.And(gom.Eq("_id", lockID), gom.Eq("Owner", m.params.Owner))).
+		Cmd().
+		DeleteOne()
+}
+
+// migrationError = wrap error of migration with its version and direction
+func migrationError(direction string, mig Migration, err error) error {
+	return &Error{
+		Direction: direction,
+		Version:   mig.Version,
+		Err:       err,
+	}
+}
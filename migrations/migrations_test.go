@@ -0,0 +1,181 @@
+package migrations
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ariefsn/gom"
+)
+
+// noop = migration step doing nothing
+func noop(ctx context.Context, g *gom.Gom) error {
+	return nil
+}
+
+// versions = get versions of migrations
+func versions(migrations []Migration) []int64 {
+	res := []int64{}
+
+	for _, mig := range migrations {
+		res = append(res, mig.Version)
+	}
+
+	return res
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		want       []int64
+		wantErr    string
+	}{
+		{
+			name:       "sorted by version",
+			migrations: []Migration{{Version: 3, Up: noop}, {Version: 1, Up: noop}, {Version: 2, Up: noop}},
+			want:       []int64{1, 2, 3},
+		},
+		{
+			name:       "zero version",
+			migrations: []Migration{{Version: 0, Up: noop}},
+			wantErr:    "invalid migration version: 0",
+		},
+		{
+			name:       "negative version",
+			migrations: []Migration{{Version: -1, Up: noop}},
+			wantErr:    "invalid migration version: -1",
+		},
+		{
+			name:       "duplicate version",
+			migrations: []Migration{{Version: 1, Up: noop}, {Version: 1, Up: noop}},
+			wantErr:    "duplicate migration version: 1",
+		},
+		{
+			name:       "missing Up",
+			migrations: []Migration{{Version: 1, Down: noop}},
+			wantErr:    "migration 1 has no Up",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(nil, nil)
+			err := m.Register(tt.migrations...)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := versions(m.migrations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterDuplicateOfPreviousCall(t *testing.T) {
+	m := New(nil, nil)
+
+	err := m.Register(Migration{Version: 1, Up: noop})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = m.Register(Migration{Version: 2, Up: noop}, Migration{Version: 1, Up: noop})
+
+	if err == nil || !strings.Contains(err.Error(), "duplicate migration version: 1") {
+		t.Fatalf("got error %v, want duplicate version error", err)
+	}
+}
+
+func TestSelectPending(t *testing.T) {
+	migrations := []Migration{{Version: 1, Up: noop}, {Version: 2, Up: noop}, {Version: 3, Up: noop}, {Version: 4, Up: noop}}
+
+	tests := []struct {
+		name    string
+		applied []Record
+		version int64
+		want    []int64
+	}{
+		{name: "nothing applied", want: []int64{1, 2, 3, 4}},
+		{name: "some applied", applied: []Record{{Version: 1}, {Version: 2}}, want: []int64{3, 4}},
+		{name: "gap is pending", applied: []Record{{Version: 1}, {Version: 3}}, want: []int64{2, 4}},
+		{name: "up to version", version: 2, want: []int64{1, 2}},
+		{name: "up to version between registered", applied: []Record{{Version: 1}}, version: 3, want: []int64{2, 3}},
+		{name: "up to applied version", applied: []Record{{Version: 1}, {Version: 2}}, version: 2, want: []int64{}},
+		{name: "all applied", applied: []Record{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}, want: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := versions(selectPending(migrations, tt.applied, tt.version)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectRevert(t *testing.T) {
+	migrations := []Migration{{Version: 1, Up: noop}, {Version: 2, Up: noop, Down: noop}, {Version: 3, Up: noop, Down: noop}}
+	applied := []Record{{Version: 1}, {Version: 2}, {Version: 3}}
+
+	tests := []struct {
+		name    string
+		applied []Record
+		steps   int
+		want    []int64
+		wantErr string
+	}{
+		{name: "last one", applied: applied, steps: 1, want: []int64{3}},
+		{name: "in revert order", applied: applied, steps: 2, want: []int64{3, 2}},
+		{name: "more steps than applied", applied: applied[1:], steps: 5, want: []int64{3, 2}},
+		{name: "nothing applied", steps: 1, want: []int64{}},
+		{name: "without Down", applied: applied, steps: 3, wantErr: "migration 1 can't be reverted"},
+		{name: "not registered", applied: []Record{{Version: 9}}, steps: 1, wantErr: "migration 9 can't be reverted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectRevert(migrations, tt.applied, tt.steps)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+
+				if got != nil {
+					t.Errorf("got %v, want nothing to revert", versions(got))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := versions(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownSteps(t *testing.T) {
+	_, err := New(nil, nil).Down(context.Background(), 0)
+
+	if err == nil || err.Error() != "steps must be positive" {
+		t.Fatalf("got error %v, want steps error", err)
+	}
+}
@@ -2,10 +2,13 @@ package gom
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net/url"
 	"reflect"
+	"strings"
 
+	"github.com/eaciit/toolkit"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -37,6 +40,8 @@ type mongoDB struct {
 
 // Config = Mongo config struct
 type Config struct {
+	// URI = full connection string, eg: mongodb://host1,host2/db?replicaSet=rs0. Can't be combined with Host and Port
+	URI             string
 	Username        string
 	Password        string
 	Host            string
@@ -45,6 +50,8 @@ type Config struct {
 	MaxPool         int
 	AuthMechanism   string
 	RegistryBuilder bool
+	// TLS = enable TLS with given CA and client certificate, required for MongoDbX509 auth mechanism
+	TLS *TLSConfig
 }
 
 // newMongo = Init new mongo
@@ -57,20 +64,197 @@ func (m *mongoDB) SetConfig(config Config) {
 	m.Config = config
 }
 
-// SetClient = Set client
-func (m *mongoDB) SetClient() {
+// parseURI = parse and validate URI of config once, database of URI is used if config Database is empty. Options of URI are returned too
+func (m *mongoDB) parseURI() (*options.ClientOptions, url.Values, error) {
 	config := m.Config
 
+	if config.Host != "" || config.Port != 0 {
+		return nil, nil, errors.New("config URI can't be combined with Host or Port")
+	}
+
+	// mongodb+srv URI is resolved by DNS lookup here, so it's applied only once
+	clientOptions := options.Client().ApplyURI(config.URI)
+	err := clientOptions.Validate()
+
+	if err != nil {
+		return nil, nil, errors.New(toolkit.Sprintf("Invalid connection URI: %s", err.Error()))
+	}
+
+	database, values, err := parseURIPath(clientOptions.GetURI())
+
+	if err != nil {
+		return nil, nil, errors.New(toolkit.Sprintf("Invalid connection URI: %s", err.Error()))
+	}
+
+	if m.Config.Database == "" {
+		m.Config.Database = database
+	}
+
+	if m.Config.Database == "" {
+		return nil, nil, errors.New("database must be set in config or URI")
+	}
+
+	return clientOptions, values, nil
+}
+
+// buildConnectionString = build connection string from Host, Port and credential of config
+func (m *mongoDB) buildConnectionString() (string, error) {
+	config := m.Config
+
+	if config.Host == "" {
+		return "", errors.New("config Host or URI must be set")
+	}
+
 	connectionString := fmt.Sprintf("mongodb://%s:%v", config.Host, config.Port)
 
-	if config.Username != "" {
+	if config.Username != "" && config.AuthMechanism != MongoDbX509 {
 		connectionString = fmt.Sprintf("mongodb://%s:%s@%s:%v", config.Username, config.Password, config.Host, config.Port)
 		if config.AuthMechanism != "" {
 			connectionString = fmt.Sprintf("mongodb+srv://%s:%s@%s/%s?authMechanism=%s", config.Username, config.Password, config.Host, config.Database, config.AuthMechanism)
 		}
 	}
 
-	clientOptions := options.Client().ApplyURI(connectionString)
+	return connectionString, nil
+}
+
+// parseURIPath = get database and options of validated connection string, it's split the same way the driver does: scheme://userinfo@hosts/database?options
+func parseURIPath(uri string) (string, url.Values, error) {
+	_, rest, _ := strings.Cut(uri, "://")
+
+	if _, hosts, ok := strings.Cut(rest, "@"); ok {
+		rest = hosts
+	}
+
+	_, path, ok := strings.Cut(rest, "/")
+
+	if !ok {
+		return "", url.Values{}, nil
+	}
+
+	database, query, _ := strings.Cut(path, "?")
+
+	database, err := url.PathUnescape(database)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	values, err := url.ParseQuery(query)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return database, values, nil
+}
+
+// uriOption = get option of connection string, option names are case insensitive
+func uriOption(values url.Values, name string) (string, bool) {
+	for key := range values {
+		if strings.EqualFold(key, name) {
+			return values.Get(key), true
+		}
+	}
+
+	return "", false
+}
+
+// mergeCredential = merge Username, Password and AuthMechanism of config into credential of URI, values set in both must match
+func mergeCredential(uriAuth *options.Credential, uriValues url.Values, config Config) (options.Credential, error) {
+	credential := options.Credential{}
+
+	if uriAuth != nil {
+		credential = *uriAuth
+	} else if authSource, ok := uriOption(uriValues, "authSource"); ok {
+		credential.AuthSource = authSource
+	}
+
+	if config.Username != "" {
+		if credential.Username != "" && credential.Username != config.Username {
+			return credential, errors.New("config Username doesn't match the username of URI")
+		}
+
+		credential.Username = config.Username
+	}
+
+	if config.Password != "" {
+		if credential.PasswordSet && credential.Password != config.Password {
+			return credential, errors.New("config Password doesn't match the password of URI")
+		}
+
+		credential.Password = config.Password
+		credential.PasswordSet = true
+	}
+
+	if config.AuthMechanism != "" {
+		if credential.AuthMechanism != "" && !strings.EqualFold(credential.AuthMechanism, config.AuthMechanism) {
+			return credential, errors.New("config AuthMechanism doesn't match the authMechanism of URI")
+		}
+
+		credential.AuthMechanism = config.AuthMechanism
+	}
+
+	return credential, nil
+}
+
+// buildClientOptions = build client options, explicit config fields are merged on top of the connection string
+func (m *mongoDB) buildClientOptions() (*options.ClientOptions, error) {
+	var clientOptions *options.ClientOptions
+	var uriValues url.Values
+	var err error
+
+	connectionString := m.Config.URI
+
+	if connectionString != "" {
+		clientOptions, uriValues, err = m.parseURI()
+	} else {
+		connectionString, err = m.buildConnectionString()
+
+		if err == nil {
+			clientOptions = options.Client().ApplyURI(connectionString)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	config := m.Config
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.build()
+
+		if err != nil {
+			return nil, err
+		}
+
+		clientOptions.SetTLSConfig(tlsConfig)
+
+		if config.TLS.DisableOCSPEndpointCheck {
+			clientOptions.SetDisableOCSPEndpointCheck(true)
+		}
+	}
+
+	if config.AuthMechanism == MongoDbX509 {
+		if config.URI == "" && (config.TLS == nil || !config.TLS.hasClientCertificate()) {
+			return nil, errors.New("MONGODB-X509 auth mechanism requires TLS client certificate")
+		}
+
+		// username is optional, server takes it from the certificate subject
+		clientOptions.SetAuth(options.Credential{
+			AuthMechanism: MongoDbX509,
+			AuthSource:    "$external",
+			Username:      config.Username,
+		})
+	} else if config.URI != "" && (config.Username != "" || config.Password != "" || config.AuthMechanism != "") {
+		credential, err := mergeCredential(clientOptions.Auth, uriValues, config)
+
+		if err != nil {
+			return nil, err
+		}
+
+		clientOptions.SetAuth(credential)
+	}
 
 	if config.MaxPool > 0 {
 		clientOptions.SetMaxPoolSize(uint64(config.MaxPool))
@@ -84,19 +268,38 @@ func (m *mongoDB) SetClient() {
 		clientOptions.SetRegistry(rb.Build())
 	}
 
-	client, err := mongo.NewClient(clientOptions)
+	err = clientOptions.Validate()
 
 	if err != nil {
-		log.Fatal(err)
+		return nil, errors.New(toolkit.Sprintf("Invalid client options: %s", err.Error()))
 	}
 
-	err = client.Connect(context.Background())
+	m.ConnectionString = connectionString
+
+	return clientOptions, nil
+}
+
+// SetClient = Set client and connect it with given context
+func (m *mongoDB) SetClient(ctx context.Context) error {
+	clientOptions, err := m.buildClientOptions()
 
 	if err != nil {
-		log.Fatal(err)
+		return &ConfigError{Err: err}
 	}
 
-	m.ConnectionString = connectionString
+	client, err := mongo.NewClient(clientOptions)
+
+	if err != nil {
+		return &ConnectionError{Err: err}
+	}
+
+	err = client.Connect(ctx)
+
+	if err != nil {
+		return &ConnectionError{Err: err}
+	}
 
 	m.Client = client
+
+	return nil
 }
@@ -0,0 +1,124 @@
+package gom
+
+import "testing"
+
+func TestBuildClientOptionsURI(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        Config
+		database      string
+		username      string
+		password      string
+		authMechanism string
+		authSource    string
+		hosts         int
+	}{
+		{
+			name:       "credential of config",
+			config:     Config{URI: "mongodb://h1:27017,h2:27017/my%20db?replicaSet=rs0&authsource=admin", Username: "user", Password: "pass"},
+			database:   "my db",
+			username:   "user",
+			password:   "pass",
+			authSource: "admin",
+			hosts:      2,
+		},
+		{
+			name:     "password of config with user of URI",
+			config:   Config{URI: "mongodb://user@h1/db", Password: "pass"},
+			database: "db",
+			username: "user",
+			password: "pass",
+			hosts:    1,
+		},
+		{
+			name:          "auth mechanism of config",
+			config:        Config{URI: "mongodb://user:pass@h1/db", AuthMechanism: ScramSha256},
+			database:      "db",
+			username:      "user",
+			password:      "pass",
+			authMechanism: ScramSha256,
+			hosts:         1,
+		},
+		{
+			name:          "same values in config and URI",
+			config:        Config{URI: "mongodb://user:pass@h1/db?authMechanism=SCRAM-SHA-256", Username: "user", Password: "pass", AuthMechanism: ScramSha256},
+			database:      "db",
+			username:      "user",
+			password:      "pass",
+			authMechanism: ScramSha256,
+			hosts:         1,
+		},
+		{
+			name:     "database of config",
+			config:   Config{URI: "mongodb://user:pass@h1/db", Database: "other"},
+			database: "other",
+			username: "user",
+			password: "pass",
+			hosts:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMongo()
+			m.SetConfig(tt.config)
+
+			opts, err := m.buildClientOptions()
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if m.Config.Database != tt.database {
+				t.Errorf("got database %q, want %q", m.Config.Database, tt.database)
+			}
+
+			if opts.Auth == nil {
+				t.Fatalf("got no auth")
+			}
+
+			if opts.Auth.Username != tt.username || opts.Auth.Password != tt.password || !opts.Auth.PasswordSet {
+				t.Errorf("got user %q password %q (set %v), want %q %q", opts.Auth.Username, opts.Auth.Password, opts.Auth.PasswordSet, tt.username, tt.password)
+			}
+
+			if opts.Auth.AuthMechanism != tt.authMechanism {
+				t.Errorf("got auth mechanism %q, want %q", opts.Auth.AuthMechanism, tt.authMechanism)
+			}
+
+			if tt.authSource != "" && opts.Auth.AuthSource != tt.authSource {
+				t.Errorf("got auth source %q, want %q", opts.Auth.AuthSource, tt.authSource)
+			}
+
+			if len(opts.Hosts) != tt.hosts {
+				t.Errorf("got hosts %v, want %d hosts", opts.Hosts, tt.hosts)
+			}
+		})
+	}
+}
+
+func TestBuildClientOptionsURIErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "invalid scheme", config: Config{URI: "http://h1/db"}},
+		{name: "combined with host", config: Config{URI: "mongodb://h1/db", Host: "h2"}},
+		{name: "missing database", config: Config{URI: "mongodb://h1/?replicaSet=rs0"}},
+		{name: "different username", config: Config{URI: "mongodb://a:b@h1/db", Username: "c"}},
+		{name: "different password", config: Config{URI: "mongodb://a:b@h1/db", Password: "c"}},
+		{name: "different auth mechanism", config: Config{URI: "mongodb://a:b@h1/db?authMechanism=SCRAM-SHA-1", AuthMechanism: ScramSha256}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMongo()
+			m.SetConfig(tt.config)
+
+			_, err := m.buildClientOptions()
+
+			if err == nil {
+				t.Errorf("want error")
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package gom
 
-import "go.mongodb.org/mongo-driver/bson"
+import (
+	"github.com/eaciit/toolkit"
+	"go.mongodb.org/mongo-driver/bson"
+)
 
 // PipeUnwind = create pipe for unwind arrays. To spesify, prefix the field with dollar sign ($)
 func PipeUnwind(path string, showEmptyArrays bool) bson.M {
@@ -14,15 +17,107 @@ func PipeUnwind(path string, showEmptyArrays bool) bson.M {
 	return m
 }
 
-// PipeMatch = create pipe for match filter.
+// PipeMatch = create pipe for match filter. It returns nil if filter is invalid, use CompileMatch to get the error
 func PipeMatch(filter *Filter) bson.M {
+	m, err := CompileMatch(filter)
+
+	if err != nil {
+		return nil
+	}
+
+	return m
+}
+
+// CompileMatch = create pipe for match filter like PipeMatch and validate filter. Invalid filter returns *FilterError
+func CompileMatch(filter *Filter) (bson.M, error) {
+	match, err := Compile(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
 	m := bson.M{
-		"$match": BuildFilter(filter),
+		"$match": match,
+	}
+
+	return m, nil
+}
+
+// PipeGeoNear = create pipe for geoNear, it must be the first stage. Near field requires 2dsphere index.
+// It returns nil if params are invalid, use CompileGeoNear to get the error
+func PipeGeoNear(params PipeGeoNearParams) bson.M {
+	m, err := CompileGeoNear(params)
+
+	if err != nil {
+		return nil
 	}
 
 	return m
 }
 
+// CompileGeoNear = create pipe for geoNear like PipeGeoNear and validate params. Invalid Query returns *FilterError with path geoNear.query
+func CompileGeoNear(params PipeGeoNearParams) (bson.M, error) {
+	err := params.Near.validate()
+
+	if err != nil {
+		return nil, validationError("invalid near of geoNear: " + err.Error())
+	}
+
+	if params.DistanceField == "" {
+		return nil, validationError("distance field of geoNear can't be empty")
+	}
+
+	if params.MaxDistance > 0 && params.MinDistance > params.MaxDistance {
+		return nil, validationError("min distance of geoNear can't be greater than max distance")
+	}
+
+	geoNear := bson.M{
+		"near":          params.Near,
+		"distanceField": params.DistanceField,
+		"spherical":     true,
+	}
+
+	if params.Key != "" {
+		geoNear["key"] = params.Key
+	}
+
+	if params.MaxDistance > 0 {
+		geoNear["maxDistance"] = params.MaxDistance
+	}
+
+	if params.MinDistance > 0 {
+		geoNear["minDistance"] = params.MinDistance
+	}
+
+	if params.Query != nil {
+		query, err := compileFilter(params.Query, "geoNear.query")
+
+		if err != nil {
+			return nil, err
+		}
+
+		if path := nearPath(params.Query, "geoNear.query"); path != "" {
+			return nil, filterError(path, "$near and $nearSphere can't be used by query of geoNear")
+		}
+
+		geoNear["query"] = query
+	}
+
+	if params.IncludeLocs != "" {
+		geoNear["includeLocs"] = params.IncludeLocs
+	}
+
+	if params.DistanceMultiplier > 0 {
+		geoNear["distanceMultiplier"] = params.DistanceMultiplier
+	}
+
+	m := bson.M{
+		"$geoNear": geoNear,
+	}
+
+	return m, nil
+}
+
 // PipeLookup = create pipe for lookup to another collection.
 func PipeLookup(fromCollection, localField, foreignField, as string) bson.M {
 	m := bson.M{
@@ -102,13 +197,30 @@ func PipeProject(project bson.M) bson.M {
 	return m
 }
 
-// PipeSwitch = create pipe for switch condition.
+// PipeSwitch = create pipe for switch condition. It returns nil if any case is invalid, use CompileSwitch to get the error
 func PipeSwitch(switchCase PipeSwitchParams) bson.M {
+	m, err := CompileSwitch(switchCase)
+
+	if err != nil {
+		return nil
+	}
+
+	return m
+}
+
+// CompileSwitch = create pipe for switch condition like PipeSwitch and validate cases. Invalid case returns *FilterError with path switch.cases[i]
+func CompileSwitch(switchCase PipeSwitchParams) (bson.M, error) {
 	branches := []bson.M{}
 
-	for _, c := range switchCase.Cases {
+	for idx, c := range switchCase.Cases {
+		caseFilter, err := compileFilter(c.Case, toolkit.Sprintf("switch.cases[%d]", idx))
+
+		if err != nil {
+			return nil, err
+		}
+
 		branches = append(branches, bson.M{
-			"case": BuildFilter(c.Case),
+			"case": caseFilter,
 			"then": c.Then,
 		})
 	}
@@ -120,7 +232,7 @@ func PipeSwitch(switchCase PipeSwitchParams) bson.M {
 		},
 	}
 
-	return m
+	return m, nil
 }
 
 // PipeGroup = create pipe for group aggregation.
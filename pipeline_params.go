@@ -17,3 +17,21 @@ type PipeSwitchParams struct {
 	Cases   []PipeSwitchCaseParams
 	Default interface{}
 }
+
+// PipeGeoNearParams = params model for pipe geoNear
+type PipeGeoNearParams struct {
+	Near Point
+	// DistanceField = output field of calculated distance in meters
+	DistanceField string
+	// Key = geo indexed field, required if collection has more than one geo index
+	Key string
+	// MaxDistance, MinDistance = distance limit in meters, 0 is no limit
+	MaxDistance float64
+	MinDistance float64
+	// Query = filter documents before distance is calculated
+	Query *Filter
+	// IncludeLocs = output field of location used to calculate distance
+	IncludeLocs string
+	// DistanceMultiplier = multiply calculated distance, eg: 0.001 for kilometers
+	DistanceMultiplier float64
+}
@@ -0,0 +1,137 @@
+package gom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCompileGeoNear(t *testing.T) {
+	here := NewPoint(106.8, -6.2)
+
+	got, err := CompileGeoNear(PipeGeoNearParams{
+		Near:          here,
+		DistanceField: "Distance",
+		MaxDistance:   5000,
+		Query:         Eq("Open", true),
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := bson.M{"$geoNear": bson.M{
+		"near":          here,
+		"distanceField": "Distance",
+		"spherical":     true,
+		"maxDistance":   5000.0,
+		"query":         bson.M{"Open": bson.M{"$eq": true}},
+	}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCompileGeoNearErrors(t *testing.T) {
+	here := NewPoint(106.8, -6.2)
+
+	tests := []struct {
+		name   string
+		params PipeGeoNearParams
+		path   string
+	}{
+		{name: "missing near", params: PipeGeoNearParams{DistanceField: "d"}},
+		{name: "near out of range", params: PipeGeoNearParams{Near: NewPoint(0, 100), DistanceField: "d"}},
+		{name: "missing distance field", params: PipeGeoNearParams{Near: here}},
+		{name: "min greater than max", params: PipeGeoNearParams{Near: here, DistanceField: "d", MaxDistance: 1, MinDistance: 2}},
+		{name: "invalid query", params: PipeGeoNearParams{Near: here, DistanceField: "d", Query: And(Eq("", 1))}, path: "geoNear.query.$and[0]"},
+		{name: "near in query", params: PipeGeoNearParams{Near: here, DistanceField: "d", Query: Near("loc", here, 0, 0)}, path: "geoNear.query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileGeoNear(tt.params)
+
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("got %v, want validation error", err)
+			}
+
+			var filterErr *FilterError
+
+			if errors.As(err, &filterErr) != (tt.path != "") {
+				t.Fatalf("got %T, want *FilterError only for invalid query", err)
+			}
+
+			if tt.path != "" && filterErr.Path != tt.path {
+				t.Errorf("got path %s, want %s", filterErr.Path, tt.path)
+			}
+
+			if PipeGeoNear(tt.params) != nil {
+				t.Errorf("PipeGeoNear of invalid params must be nil")
+			}
+		})
+	}
+}
+
+func TestCompilePipeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		compile func() (bson.M, error)
+		pipe    func() bson.M
+		path    string
+	}{
+		{
+			name:    "match",
+			compile: func() (bson.M, error) { return CompileMatch(Or(Eq("a", 1), Eq("", 2))) },
+			pipe:    func() bson.M { return PipeMatch(Or(Eq("a", 1), Eq("", 2))) },
+			path:    "filter.$or[1]",
+		},
+		{
+			name: "switch",
+			compile: func() (bson.M, error) {
+				return CompileSwitch(PipeSwitchParams{Cases: []PipeSwitchCaseParams{{Case: Eq("a", 1)}, {Case: nil}}})
+			},
+			pipe: func() bson.M {
+				return PipeSwitch(PipeSwitchParams{Cases: []PipeSwitchCaseParams{{Case: Eq("a", 1)}, {Case: nil}}})
+			},
+			path: "switch.cases[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.compile()
+
+			var filterErr *FilterError
+
+			if !errors.As(err, &filterErr) {
+				t.Fatalf("got %v, want *FilterError", err)
+			}
+
+			if filterErr.Path != tt.path {
+				t.Errorf("got path %s, want %s", filterErr.Path, tt.path)
+			}
+
+			if tt.pipe() != nil {
+				t.Errorf("pipe of invalid filter must be nil")
+			}
+		})
+	}
+}
+
+func TestCompileMatch(t *testing.T) {
+	got, err := CompileMatch(Eq("a", 1))
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := bson.M{"$match": bson.M{"a": bson.M{"$eq": 1}}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
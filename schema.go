@@ -0,0 +1,412 @@
+package gom
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eaciit/toolkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// ValidationLevelStrict is validate all inserts and updates
+	ValidationLevelStrict = "strict"
+	// ValidationLevelModerate is validate inserts and updates of already valid documents
+	ValidationLevelModerate = "moderate"
+	// ValidationLevelOff is no validation
+	ValidationLevelOff = "off"
+	// ValidationActionError is reject invalid documents
+	ValidationActionError = "error"
+	// ValidationActionWarn is log invalid documents but accept them
+	ValidationActionWarn = "warn"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	objectIDType   = reflect.TypeOf(primitive.ObjectID{})
+	decimalType    = reflect.TypeOf(primitive.Decimal128{})
+	dateTimeType   = reflect.TypeOf(primitive.DateTime(0))
+	bytesType      = reflect.TypeOf([]byte{})
+	schemaTagNames = []string{"enum=", "min=", "max=", "required", "optional"}
+)
+
+// isSchemaOption = check gom tag option belongs to schema generator
+func isSchemaOption(opt string) bool {
+	for _, name := range schemaTagNames {
+		if opt == name || (strings.HasSuffix(name, "=") && strings.HasPrefix(opt, name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validator = build $jsonSchema validator of model, see JSONSchema
+func Validator(model interface{}) (bson.M, error) {
+	schema, err := JSONSchema(model)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{
+		"$jsonSchema": schema,
+	}, nil
+}
+
+// JSONSchema = generate $jsonSchema of struct model. Field names are taken from bson tag, json tag or field name.
+// Non pointer fields without omitempty are required, pointer, slice and map fields also accept null. Recursive types return validation error.
+// `gom` tag options: enum=a|b|c, min=<n>, max=<n> (value for numbers, length for strings, items for slices), required, optional
+func JSONSchema(model interface{}) (bson.M, error) {
+	rt := reflect.TypeOf(model)
+
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, validationError("model must be a struct")
+	}
+
+	return structSchema(rt, rt.Name(), map[reflect.Type]bool{})
+}
+
+// structSchema = build object schema of struct type, visiting holds struct types being built to reject recursive types
+func structSchema(rt reflect.Type, path string, visiting map[reflect.Type]bool) (bson.M, error) {
+	if visiting[rt] {
+		return nil, validationError(toolkit.Sprintf("recursive type of %s: %s", path, rt.String()))
+	}
+
+	visiting[rt] = true
+	defer delete(visiting, rt)
+
+	properties := bson.M{}
+	required := []string{}
+
+	err := addStructProperties(rt, path, properties, &required, visiting)
+
+	if err != nil {
+		return nil, err
+	}
+
+	schema := bson.M{
+		"bsonType":   "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+// addStructProperties = add properties of struct fields, inline and embedded struct fields are merged
+func addStructProperties(rt reflect.Type, path string, properties bson.M, required *[]string, visiting map[reflect.Type]bool) error {
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("bson")
+		tagOpts := strings.Split(tag, ",")
+
+		if tagOpts[0] == "-" {
+			continue
+		}
+
+		if tag == "" {
+			tag = field.Tag.Get("json")
+			tagOpts = strings.Split(tag, ",")
+
+			if tagOpts[0] == "-" {
+				continue
+			}
+		}
+
+		inline := false
+		omitEmpty := false
+
+		for _, opt := range tagOpts[1:] {
+			switch opt {
+			case "inline":
+				inline = true
+			case "omitempty":
+				omitEmpty = true
+			}
+		}
+
+		ft := field.Type
+
+		// documents are written with encoding/json, it flattens embedded struct without json name like inline
+		embedded := ft
+
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+
+		if field.Anonymous && embedded.Kind() == reflect.Struct && strings.Split(field.Tag.Get("json"), ",")[0] == "" {
+			inline = true
+		}
+
+		if field.Anonymous && embedded.Kind() != reflect.Struct && field.PkgPath != "" {
+			continue
+		}
+
+		if inline && embedded.Kind() == reflect.Struct {
+			err := addInlineProperties(embedded, path, properties, required, ft.Kind() == reflect.Ptr, visiting)
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		name := bsonFieldName(field)
+		fieldPath := path + "." + name
+
+		schema, nullable, err := typeSchema(ft, fieldPath, visiting)
+
+		if err != nil {
+			return err
+		}
+
+		isRequired := !nullable && !omitEmpty
+
+		for _, opt := range strings.Split(field.Tag.Get("gom"), ",") {
+			opt = strings.TrimSpace(opt)
+
+			switch {
+			case opt == "required":
+				isRequired = true
+			case opt == "optional":
+				isRequired = false
+			case strings.HasPrefix(opt, "enum="):
+				enum := []interface{}{}
+
+				for _, v := range strings.Split(strings.TrimPrefix(opt, "enum="), "|") {
+					ev, err := parseSchemaValue(ft, v)
+
+					if err != nil {
+						return validationError(toolkit.Sprintf("invalid enum of %s: %s", fieldPath, err.Error()))
+					}
+
+					enum = append(enum, ev)
+				}
+
+				if nullable {
+					enum = append(enum, nil)
+				}
+
+				schema["enum"] = enum
+			case strings.HasPrefix(opt, "min="), strings.HasPrefix(opt, "max="):
+				err := setSchemaBound(schema, ft, opt)
+
+				if err != nil {
+					return validationError(toolkit.Sprintf("invalid bound of %s: %s", fieldPath, err.Error()))
+				}
+			}
+		}
+
+		properties[name] = schema
+
+		if isRequired {
+			*required = append(*required, name)
+		}
+	}
+
+	return nil
+}
+
+// addInlineProperties = add properties of inline or embedded struct to parent. Fields of nil embedded pointer are missing, so they aren't required
+func addInlineProperties(rt reflect.Type, path string, properties bson.M, required *[]string, optional bool, visiting map[reflect.Type]bool) error {
+	if visiting[rt] {
+		return validationError(toolkit.Sprintf("recursive type of %s: %s", path, rt.String()))
+	}
+
+	visiting[rt] = true
+	defer delete(visiting, rt)
+
+	if optional {
+		required = &[]string{}
+	}
+
+	return addStructProperties(rt, path, properties, required, visiting)
+}
+
+// typeSchema = build schema of go type, nullable is true for pointer
+func typeSchema(rt reflect.Type, path string, visiting map[reflect.Type]bool) (bson.M, bool, error) {
+	nullable := false
+
+	for rt.Kind() == reflect.Ptr {
+		nullable = true
+		rt = rt.Elem()
+	}
+
+	schema := bson.M{}
+
+	switch {
+	case rt == timeType, rt == dateTimeType:
+		schema["bsonType"] = "date"
+	case rt == objectIDType:
+		schema["bsonType"] = "objectId"
+	case rt == decimalType:
+		schema["bsonType"] = "decimal"
+	case rt == bytesType:
+		schema["bsonType"] = "binData"
+	default:
+		switch rt.Kind() {
+		case reflect.String:
+			schema["bsonType"] = "string"
+		case reflect.Bool:
+			schema["bsonType"] = "bool"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			schema["bsonType"] = []string{"int", "long"}
+		case reflect.Float32, reflect.Float64:
+			schema["bsonType"] = "number"
+		case reflect.Slice, reflect.Array:
+			schema["bsonType"] = "array"
+
+			items, _, err := typeSchema(rt.Elem(), path+"[]", visiting)
+
+			if err != nil {
+				return nil, false, err
+			}
+
+			if len(items) > 0 {
+				schema["items"] = items
+			}
+		case reflect.Map:
+			schema["bsonType"] = "object"
+		case reflect.Struct:
+			obj, err := structSchema(rt, path, visiting)
+
+			if err != nil {
+				return nil, false, err
+			}
+
+			schema = obj
+		case reflect.Interface:
+			// any type
+		default:
+			return nil, false, validationError(toolkit.Sprintf("unsupported type of %s: %s", path, rt.String()))
+		}
+	}
+
+	// nil slice and map are stored as null
+	canBeNull := nullable || (rt != bytesType && (rt.Kind() == reflect.Slice || rt.Kind() == reflect.Map))
+
+	if canBeNull && schema["bsonType"] != nil {
+		schema["bsonType"] = appendBsonType(schema["bsonType"], "null")
+	}
+
+	return schema, nullable, nil
+}
+
+// appendBsonType = append type to single or multiple bsonType
+func appendBsonType(bsonType interface{}, t string) []string {
+	if types, ok := bsonType.([]string); ok {
+		return append(append([]string{}, types...), t)
+	}
+
+	return []string{bsonType.(string), t}
+}
+
+// parseSchemaValue = parse tag value by field kind
+func parseSchemaValue(rt reflect.Type, v string) (interface{}, error) {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(v, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(v, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(v)
+	}
+
+	return v, nil
+}
+
+// setSchemaBound = set min/max keyword by field kind
+func setSchemaBound(schema bson.M, rt reflect.Type, opt string) error {
+	isMin := strings.HasPrefix(opt, "min=")
+	v := opt[4:]
+
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	switch rt.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array:
+		n, err := strconv.ParseInt(v, 10, 64)
+
+		if err != nil {
+			return err
+		}
+
+		key := "Length"
+
+		if rt.Kind() != reflect.String {
+			key = "Items"
+		}
+
+		if isMin {
+			schema["min"+key] = n
+		} else {
+			schema["max"+key] = n
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		n, err := parseSchemaValue(rt, v)
+
+		if err != nil {
+			return err
+		}
+
+		if isMin {
+			schema["minimum"] = n
+		} else {
+			schema["maximum"] = n
+		}
+	default:
+		return validationError(toolkit.Sprintf("min/max not supported for %s", rt.String()))
+	}
+
+	return nil
+}
+
+// SetValidator = apply validator to existing table with collMod, level and action can be empty to keep server default
+func (c *Command) SetValidator(validator bson.M, level, action string) error {
+	if c.set.tableName == "" {
+		return validationError("table name not defined")
+	}
+
+	command := bson.D{
+		{Key: "collMod", Value: c.set.tableName},
+		{Key: "validator", Value: validator},
+	}
+
+	if level != "" {
+		command = append(command, bson.E{Key: "validationLevel", Value: level})
+	}
+
+	if action != "" {
+		command = append(command, bson.E{Key: "validationAction", Value: action})
+	}
+
+	_, err := c.runCommand("Error setting validator", command)
+
+	return err
+}
@@ -0,0 +1,159 @@
+package gom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type schemaAddress struct {
+	City string `bson:"city"`
+}
+
+type schemaModel struct {
+	ID      primitive.ObjectID `bson:"_id"`
+	Name    string             `bson:"Name" gom:"index,min=1,max=50"`
+	Team    string             `bson:"Team" gom:"enum=a|b"`
+	Age     int                `bson:"Age" gom:"min=0"`
+	Score   *float64           `bson:"Score"`
+	Tags    []string           `bson:"Tags,omitempty" gom:"max=3"`
+	Created time.Time          `bson:"Created"`
+	Address schemaAddress      `bson:"Address"`
+	Skip    string             `bson:"-"`
+}
+
+type schemaNode struct {
+	Name     string        `bson:"Name"`
+	Children []*schemaNode `bson:"Children"`
+}
+
+type schemaParent struct {
+	Child schemaChild `bson:"Child"`
+}
+
+type schemaChild struct {
+	Parent *schemaParent `bson:"Parent"`
+}
+
+type schemaBase struct {
+	Code string `bson:"Code"`
+}
+
+type schemaExtra struct {
+	Note string `bson:"Note"`
+}
+
+type schemaEmbedded struct {
+	schemaBase
+	*schemaExtra
+	Named schemaAddress `json:"named" bson:"named"`
+	Age   int           `bson:"Age"`
+}
+
+type schemaSiblings struct {
+	Home schemaAddress `bson:"Home"`
+	Work schemaAddress `bson:"Work"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema, err := JSONSchema(&schemaModel{})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	properties := schema["properties"].(bson.M)
+
+	tests := []struct {
+		field string
+		want  bson.M
+	}{
+		{field: "_id", want: bson.M{"bsonType": "objectId"}},
+		{field: "Name", want: bson.M{"bsonType": "string", "minLength": int64(1), "maxLength": int64(50)}},
+		{field: "Team", want: bson.M{"bsonType": "string", "enum": []interface{}{"a", "b"}}},
+		{field: "Age", want: bson.M{"bsonType": []string{"int", "long"}, "minimum": int64(0)}},
+		{field: "Score", want: bson.M{"bsonType": []string{"number", "null"}}},
+		{field: "Tags", want: bson.M{"bsonType": []string{"array", "null"}, "items": bson.M{"bsonType": "string"}, "maxItems": int64(3)}},
+		{field: "Created", want: bson.M{"bsonType": "date"}},
+		{field: "Address", want: bson.M{"bsonType": "object", "properties": bson.M{"city": bson.M{"bsonType": "string"}}, "required": []string{"city"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if !reflect.DeepEqual(properties[tt.field], tt.want) {
+				t.Errorf("got %v, want %v", properties[tt.field], tt.want)
+			}
+		})
+	}
+
+	if _, ok := properties["Skip"]; ok {
+		t.Errorf("field with bson:\"-\" must be skipped")
+	}
+
+	wantRequired := []string{"_id", "Name", "Team", "Age", "Created", "Address"}
+
+	if !reflect.DeepEqual(schema["required"], wantRequired) {
+		t.Errorf("got required %v, want %v", schema["required"], wantRequired)
+	}
+}
+
+func TestJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		model interface{}
+	}{
+		{name: "not a struct", model: "x"},
+		{name: "self referencing", model: &schemaNode{}},
+		{name: "mutually referencing", model: &schemaParent{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := JSONSchema(tt.model)
+
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("got %v, want validation error", err)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaRepeatedType(t *testing.T) {
+	_, err := JSONSchema(&schemaSiblings{})
+
+	if err != nil {
+		t.Errorf("same struct type in sibling fields isn't recursive, got %v", err)
+	}
+}
+
+func TestJSONSchemaEmbedded(t *testing.T) {
+	schema, err := JSONSchema(&schemaEmbedded{})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	properties := schema["properties"].(bson.M)
+
+	for _, field := range []string{"Code", "Note", "named", "Age"} {
+		if _, ok := properties[field]; !ok {
+			t.Errorf("missing property %s", field)
+		}
+	}
+
+	for _, field := range []string{"schemaBase", "schemaExtra"} {
+		if _, ok := properties[field]; ok {
+			t.Errorf("embedded struct %s must be flattened", field)
+		}
+	}
+
+	wantRequired := []string{"Code", "named", "Age"}
+
+	if !reflect.DeepEqual(schema["required"], wantRequired) {
+		t.Errorf("got required %v, want %v", schema["required"], wantRequired)
+	}
+}
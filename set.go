@@ -3,14 +3,14 @@ package gom
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/eaciit/toolkit"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -26,8 +26,13 @@ type Set struct {
 	sortBy         *int
 	skip           *int
 	limit          *int
+	batchSize      *int32
+	projection     bson.M
 	command        *Command
 	contextTimeout time.Duration
+	ctx            context.Context
+	err            error
+	nearPath       string
 }
 
 // newSet = init new set
@@ -61,6 +66,14 @@ func newSet(gom *Gom, params *SetParams) *Set {
 			s.Limit(params.Limit)
 		}
 
+		if params.BatchSize != 0 {
+			s.BatchSize(params.BatchSize)
+		}
+
+		if params.Projection != nil {
+			s.Project(params.Projection)
+		}
+
 		if params.Result != nil {
 			s.Result(params.Result)
 		}
@@ -73,6 +86,10 @@ func newSet(gom *Gom, params *SetParams) *Set {
 			s.Sort(params.SortField, params.SortBy)
 		}
 
+		if params.Context != nil {
+			s.Context(params.Context)
+		}
+
 		if params.Timeout == 0 {
 			s.Timeout(30)
 		} else {
@@ -89,12 +106,17 @@ func newSet(gom *Gom, params *SetParams) *Set {
 func (s *Set) reset() {
 	s.filter = bson.M{}
 	s.limit = nil
+	s.batchSize = nil
+	s.projection = nil
 	s.pipe = nil
 	s.result = nil
 	s.skip = nil
 	s.sortBy = nil
 	s.sortField = nil
 	s.tableName = ""
+	s.ctx = nil
+	s.err = nil
+	s.nearPath = ""
 }
 
 // Table = set table/collection name
@@ -130,6 +152,14 @@ func (s *Set) Limit(limit int) *Set {
 	return s
 }
 
+// BatchSize = set number of documents fetched per batch by Cursor and Each
+func (s *Set) BatchSize(batchSize int) *Set {
+	size := int32(batchSize)
+	s.batchSize = &size
+
+	return s
+}
+
 // Sort = set sort data
 func (s *Set) Sort(field, sortBy string) *Set {
 	s.sortField = &field
@@ -144,14 +174,29 @@ func (s *Set) Sort(field, sortBy string) *Set {
 	return s
 }
 
-// Filter = set filter data
+// Project = set projection of FindOneAndUpdate, FindOneAndReplace and FindOneAndDelete
+func (s *Set) Project(projection bson.M) *Set {
+	s.projection = projection
+
+	return s
+}
+
+// Filter = set filter data, error of invalid filter is returned by the command
 func (s *Set) Filter(filter *Filter) *Set {
 
 	if filter != nil {
-		main := BuildFilter(filter)
+		main, err := Compile(filter)
 		s.filter = main
+		s.err = err
+		s.nearPath = ""
+
+		if err == nil {
+			s.nearPath = nearPath(filter, "filter")
+		}
 	} else {
 		s.filter = bson.M{}
+		s.err = nil
+		s.nearPath = ""
 	}
 
 	return s
@@ -200,6 +245,17 @@ func (s *Set) buildPipe() []bson.M {
 	return pipe
 }
 
+// buildSort = build sort document, nil if sort not set
+func (s *Set) buildSort() bson.M {
+	if s.sortField == nil {
+		return nil
+	}
+
+	return bson.M{
+		*s.sortField: *s.sortBy,
+	}
+}
+
 func getValidID(key string) string {
 	if key == "ID" || key == "_id" || key == "id" {
 		return "_id"
@@ -250,13 +306,11 @@ func validateJSONRaw(k string, v json.RawMessage, m bson.M) {
 	var slice []json.RawMessage
 	err = json.Unmarshal(v, &slice)
 	if err == nil {
-		tempBsonM := bson.M{}
 		validSlice := []interface{}{}
 		for _, elSlice := range slice {
-			validateJSONRaw(toolkit.RandomString(32), elSlice, tempBsonM)
-		}
-		for _, vo := range tempBsonM {
-			validSlice = append(validSlice, vo)
+			tempBsonM := bson.M{}
+			validateJSONRaw("v", elSlice, tempBsonM)
+			validSlice = append(validSlice, tempBsonM["v"])
 		}
 
 		m[getValidID(k)] = validSlice
@@ -279,7 +333,7 @@ func (s *Set) buildData(data interface{}, includeID bool) (interface{}, error) {
 	rv := reflect.ValueOf(data)
 
 	if rv.Kind() != reflect.Ptr {
-		return nil, errors.New("data argument must be pointer")
+		return nil, validationError("data argument must be pointer")
 	}
 
 	switch rv.Elem().Kind() {
@@ -294,7 +348,7 @@ func (s *Set) buildData(data interface{}, includeID bool) (interface{}, error) {
 			if includeID {
 				validateJSONRaw(k, v, dataM)
 			} else {
-				if k != "_id" {
+				if getValidID(k) != "_id" {
 					validateJSONRaw(k, v, dataM)
 				}
 			}
@@ -309,7 +363,7 @@ func (s *Set) buildData(data interface{}, includeID bool) (interface{}, error) {
 			if includeID {
 				dataM[getValidID(key.String())] = value.Interface()
 			} else {
-				if key.String() != "_id" {
+				if getValidID(key.String()) != "_id" {
 					dataM[getValidID(key.String())] = value.Interface()
 				}
 			}
@@ -329,16 +383,65 @@ func (s *Set) buildData(data interface{}, includeID bool) (interface{}, error) {
 		result = datas
 
 	default:
-		return nil, errors.New("data argument must be a struct or map")
+		return nil, validationError("data argument must be a struct or map")
 	}
 
 	if result == nil {
-		return nil, errors.New("data argument can't be empty")
+		return nil, validationError("data argument can't be empty")
 	}
 
 	return result, nil
 }
 
+// buildDocument = build document of insert or replace from struct/map pointer. *Update and struct without fields are rejected
+// because they would be written as empty document
+func (s *Set) buildDocument(data interface{}, includeID bool) (bson.M, error) {
+	if _, ok := data.(*Update); ok {
+		return nil, validationError("data argument can't be *Update, use Update or FindOneAndUpdate")
+	}
+
+	dataM, err := s.buildData(data, includeID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := dataM.(bson.M)
+
+	if !ok {
+		return nil, validationError("data argument must be a struct or map")
+	}
+
+	if len(m) == 0 && reflect.ValueOf(data).Elem().Kind() == reflect.Struct {
+		return nil, validationError("data argument has no fields to write")
+	}
+
+	return m, nil
+}
+
+// buildUpdate = build update document from *Update, or $set of struct/map
+func (s *Set) buildUpdate(data interface{}) (bson.M, *options.UpdateOptions, error) {
+	if u, ok := data.(*Update); ok {
+		update, err := BuildUpdate(u)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return update, u.updateOptions(), nil
+	}
+
+	dataM, err := s.buildData(data, false)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bson.M{
+		"$set": dataM,
+	}, options.Update(), nil
+}
+
 // Timeout = Timeout for command
 func (s *Set) Timeout(seconds time.Duration) *Set {
 	if &seconds == nil {
@@ -350,9 +453,35 @@ func (s *Set) Timeout(seconds time.Duration) *Set {
 	return s
 }
 
-// GetContext = GetContext for command
+// Context = set parent context for command, Timeout is applied on top of it
+func (s *Set) Context(ctx context.Context) *Set {
+	s.ctx = ctx
+
+	return s
+}
+
+// parentContext = Context if set or context.Background, without timeout. Inside transaction the session is attached
+func (s *Set) parentContext() context.Context {
+	parent := s.ctx
+
+	if parent == nil {
+		parent = s.gom.txCtx
+	}
+
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	if s.gom.session != nil {
+		parent = mongo.NewSessionContext(parent, s.gom.session)
+	}
+
+	return parent
+}
+
+// GetContext = GetContext for command, derived from Context if set or context.Background. Inside transaction the session is attached
 func (s *Set) GetContext() (context.Context, context.CancelFunc) {
-	ctx, cancelFunc := context.WithTimeout(context.Background(), s.contextTimeout*time.Second)
+	ctx, cancelFunc := context.WithTimeout(s.parentContext(), s.contextTimeout*time.Second)
 
 	return ctx, cancelFunc
 }
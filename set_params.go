@@ -1,6 +1,7 @@
 package gom
 
 import (
+	"context"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -8,15 +9,18 @@ import (
 
 // SetParams = parameters that optionally pass to Set method
 type SetParams struct {
-	TableName string
-	Result    interface{}
-	Filter    *Filter
-	Pipe      []bson.M
-	SortField string
-	SortBy    string
-	Skip      int
-	Limit     int
-	Timeout   time.Duration
+	TableName  string
+	Result     interface{}
+	Filter     *Filter
+	Pipe       []bson.M
+	SortField  string
+	SortBy     string
+	Skip       int
+	Limit      int
+	BatchSize  int
+	Projection bson.M
+	Timeout    time.Duration
+	Context    context.Context
 }
 
 // NewSetParams = Init set params
@@ -0,0 +1,73 @@
+package gom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBuildDataWithoutID(t *testing.T) {
+	type model struct {
+		ID   string
+		Name string
+	}
+
+	tests := []struct {
+		name string
+		data interface{}
+	}{
+		{name: "struct", data: &model{ID: "1", Name: "a"}},
+		{name: "map of _id", data: &bson.M{"_id": "1", "Name": "a"}},
+		{name: "map of ID", data: &bson.M{"ID": "1", "Name": "a"}},
+		{name: "map of id", data: &map[string]interface{}{"id": "1", "Name": "a"}},
+	}
+
+	want := bson.M{"Name": "a"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := new(Set).buildData(tt.data, false)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBuildDocumentErrors(t *testing.T) {
+	type empty struct {
+		hidden string
+	}
+
+	type onlyID struct {
+		ID string `json:"_id"`
+	}
+
+	tests := []struct {
+		name      string
+		data      interface{}
+		includeID bool
+	}{
+		{name: "update", data: NewUpdate().Set("a", 1), includeID: true},
+		{name: "struct without fields", data: &empty{hidden: "x"}, includeID: true},
+		{name: "struct of only ignored id", data: &onlyID{ID: "1"}},
+		{name: "slice", data: &[]bson.M{{"a": 1}}, includeID: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := new(Set).buildDocument(tt.data, tt.includeID)
+
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("got %v, want validation error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package gom
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/eaciit/toolkit"
+)
+
+// TLSConfig = TLS and X.509 client certificate config. Every PEM can be loaded from file or given as bytes
+type TLSConfig struct {
+	// CAFile = path of CA bundle used to verify the server
+	CAFile string
+	// CAPEM = CA bundle bytes, used instead of CAFile
+	CAPEM []byte
+	// CertFile = path of client certificate, may also contain the private key
+	CertFile string
+	// KeyFile = path of client private key, if empty the key is read from CertFile
+	KeyFile string
+	// CertPEM = client certificate bytes, used instead of CertFile
+	CertPEM []byte
+	// KeyPEM = client private key bytes, if empty the key is read from CertPEM
+	KeyPEM []byte
+	// ServerName = override server name used to verify the server certificate
+	ServerName string
+	// InsecureSkipVerify = skip server certificate verification, for testing only
+	InsecureSkipVerify bool
+	// DisableOCSPEndpointCheck = don't reach out to OCSP responders when verifying the server certificate
+	DisableOCSPEndpointCheck bool
+}
+
+// hasClientCertificate = check client certificate is configured
+func (t *TLSConfig) hasClientCertificate() bool {
+	return t.CertFile != "" || len(t.CertPEM) > 0
+}
+
+// readPEM = read pem from bytes or file
+func readPEM(name, file string, pem []byte) ([]byte, error) {
+	if file != "" && len(pem) > 0 {
+		return nil, errors.New(toolkit.Sprintf("TLS %sFile and %sPEM can't be both set", name, name))
+	}
+
+	if len(pem) > 0 {
+		return pem, nil
+	}
+
+	if file == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, errors.New(toolkit.Sprintf("Can't read TLS %s file: %s", name, err.Error()))
+	}
+
+	return b, nil
+}
+
+// build = build crypto tls config and validate the certificates
+func (t *TLSConfig) build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+
+	caPEM, err := readPEM("CA", t.CAFile, t.CAPEM)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if caPEM != nil {
+		pool := x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("TLS CA doesn't contain any valid PEM certificate")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	certPEM, err := readPEM("Cert", t.CertFile, t.CertPEM)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := readPEM("Key", t.KeyFile, t.KeyPEM)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if certPEM == nil {
+		if keyPEM != nil {
+			return nil, errors.New("TLS key is set without client certificate")
+		}
+
+		return tlsConfig, nil
+	}
+
+	if keyPEM == nil {
+		keyPEM = certPEM
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+
+	if err != nil {
+		return nil, errors.New(toolkit.Sprintf("Invalid TLS client certificate/key pair: %s", err.Error()))
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+
+	if err != nil {
+		return nil, errors.New(toolkit.Sprintf("Invalid TLS client certificate: %s", err.Error()))
+	}
+
+	now := time.Now()
+
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return nil, errors.New(toolkit.Sprintf("TLS client certificate is not valid at this time, valid from %s until %s", leaf.NotBefore, leaf.NotAfter))
+	}
+
+	cert.Leaf = leaf
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,138 @@
+package gom
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificate = create certificate signed by parent, or self signed CA when parent is nil. Return certificate and key PEM
+func testCertificate(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, notBefore, notAfter time.Time) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		template.Subject.CommonName = "ca"
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return cert, key, certPEM, keyPEM
+}
+
+func TestTLSConfigBuild(t *testing.T) {
+	now := time.Now()
+
+	ca, caKey, caPEM, _ := testCertificate(t, nil, nil, now.Add(-time.Hour), now.Add(time.Hour))
+	_, _, certPEM, keyPEM := testCertificate(t, ca, caKey, now.Add(-time.Hour), now.Add(time.Hour))
+	_, _, _, otherKeyPEM := testCertificate(t, ca, caKey, now.Add(-time.Hour), now.Add(time.Hour))
+	_, _, expiredPEM, expiredKeyPEM := testCertificate(t, ca, caKey, now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+
+	if err := os.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := os.WriteFile(certFile, append(append([]byte{}, certPEM...), keyPEM...), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		config  TLSConfig
+		certs   int
+		wantErr string
+	}{
+		{name: "CA only", config: TLSConfig{CAPEM: caPEM}, certs: 0},
+		{name: "cert and key", config: TLSConfig{CAPEM: caPEM, CertPEM: certPEM, KeyPEM: keyPEM}, certs: 1},
+		{name: "cert and key in one PEM", config: TLSConfig{CertPEM: append(append([]byte{}, certPEM...), keyPEM...)}, certs: 1},
+		{name: "cert and key in one file", config: TLSConfig{CAFile: caFile, CertFile: certFile}, certs: 1},
+		{name: "CA file and PEM", config: TLSConfig{CAFile: caFile, CAPEM: caPEM}, wantErr: "can't be both set"},
+		{name: "cert file and PEM", config: TLSConfig{CertFile: certFile, CertPEM: certPEM, KeyPEM: keyPEM}, wantErr: "can't be both set"},
+		{name: "missing file", config: TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}, wantErr: "Can't read TLS CA file"},
+		{name: "invalid CA", config: TLSConfig{CAPEM: []byte("not a certificate")}, wantErr: "doesn't contain any valid PEM certificate"},
+		{name: "key without cert", config: TLSConfig{KeyPEM: keyPEM}, wantErr: "key is set without client certificate"},
+		{name: "cert without key", config: TLSConfig{CertPEM: certPEM}, wantErr: "Invalid TLS client certificate/key pair"},
+		{name: "invalid pair", config: TLSConfig{CertPEM: certPEM, KeyPEM: otherKeyPEM}, wantErr: "Invalid TLS client certificate/key pair"},
+		{name: "expired cert", config: TLSConfig{CertPEM: expiredPEM, KeyPEM: expiredKeyPEM}, wantErr: "is not valid at this time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.build()
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got.Certificates) != tt.certs {
+				t.Fatalf("got %d certificates, want %d", len(got.Certificates), tt.certs)
+			}
+
+			if tt.certs > 0 && got.Certificates[0].Leaf == nil {
+				t.Errorf("leaf certificate isn't set")
+			}
+
+			if (tt.config.CAPEM != nil || tt.config.CAFile != "") && got.RootCAs == nil {
+				t.Errorf("root CAs aren't set")
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package gom
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// TransactionParams = optional params of transaction, unset fields use client defaults
+type TransactionParams struct {
+	ReadConcern    *readconcern.ReadConcern
+	WriteConcern   *writeconcern.WriteConcern
+	ReadPreference *readpref.ReadPref
+}
+
+// Transaction = manually controlled transaction, finish it with Commit or Abort
+type Transaction struct {
+	gom     *Gom
+	session mongo.Session
+}
+
+// transactionOptions = build driver transaction options
+func (p *TransactionParams) transactionOptions() *options.TransactionOptions {
+	opts := options.Transaction()
+
+	if p == nil {
+		return opts
+	}
+
+	if p.ReadConcern != nil {
+		opts.SetReadConcern(p.ReadConcern)
+	}
+
+	if p.WriteConcern != nil {
+		opts.SetWriteConcern(p.WriteConcern)
+	}
+
+	if p.ReadPreference != nil {
+		opts.SetReadPreference(p.ReadPreference)
+	}
+
+	return opts
+}
+
+// newTransactionGom = create gom bound to session, every Set of it runs on the session
+func (g *Gom) newTransactionGom(ctx context.Context, session mongo.Session) *Gom {
+	tx := new(Gom)
+	tx.mongo = g.mongo
+	tx.parent = g
+	tx.session = session
+	tx.txCtx = ctx
+
+	return tx
+}
+
+// startSession = start session on connected client
+func (g *Gom) startSession() (mongo.Session, error) {
+	if g.parent != nil {
+		return nil, ErrInTransaction
+	}
+
+	err := g.acquire()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer g.release()
+
+	session, err := g.mongo.Client.StartSession()
+
+	if err != nil {
+		return nil, wrapError("Error starting session", err)
+	}
+
+	return session, nil
+}
+
+// WithTransaction = run fn in transaction. Every Set of tx runs on the transaction session. It commits when fn returns nil, aborts when fn returns error,
+// and retries fn on transient transaction errors, so fn must be safe to run more than once. tx must not be used concurrently
+func (g *Gom) WithTransaction(ctx context.Context, fn func(tx *Gom) error, params *TransactionParams) error {
+	session, err := g.startSession()
+
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(context.Background())
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(g.newTransactionGom(sessCtx, session))
+	}, params.transactionOptions())
+
+	if err != nil {
+		return wrapError("Error running transaction", err)
+	}
+
+	return nil
+}
+
+// StartTransaction = start manually controlled transaction, use Gom of it for commands then call Commit or Abort
+func (g *Gom) StartTransaction(ctx context.Context, params *TransactionParams) (*Transaction, error) {
+	session, err := g.startSession()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = session.StartTransaction(params.transactionOptions())
+
+	if err != nil {
+		session.EndSession(context.Background())
+		return nil, wrapError("Error starting transaction", err)
+	}
+
+	t := new(Transaction)
+	t.session = session
+	t.gom = g.newTransactionGom(ctx, session)
+
+	return t, nil
+}
+
+// Gom = get gom bound to this transaction
+func (t *Transaction) Gom() *Gom {
+	return t.gom
+}
+
+// Commit = commit transaction and end its session
+func (t *Transaction) Commit(ctx context.Context) error {
+	defer t.session.EndSession(context.Background())
+
+	err := t.session.CommitTransaction(ctx)
+
+	if err != nil {
+		return wrapError("Error committing transaction", err)
+	}
+
+	return nil
+}
+
+// Abort = abort transaction and end its session
+func (t *Transaction) Abort(ctx context.Context) error {
+	defer t.session.EndSession(context.Background())
+
+	err := t.session.AbortTransaction(ctx)
+
+	if err != nil {
+		return wrapError("Error aborting transaction", err)
+	}
+
+	return nil
+}
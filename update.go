@@ -0,0 +1,295 @@
+package gom
+
+import (
+	"strings"
+
+	"github.com/eaciit/toolkit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UpdateOp is string represent enumeration of supported update operator
+type UpdateOp string
+
+const (
+	// UpSet is Set
+	UpSet UpdateOp = "$set"
+	// UpUnset is Unset
+	UpUnset UpdateOp = "$unset"
+	// UpSetOnInsert is Set on insert, only applied when upsert inserts a document
+	UpSetOnInsert UpdateOp = "$setOnInsert"
+	// UpInc is Increment
+	UpInc UpdateOp = "$inc"
+	// UpMul is Multiply
+	UpMul UpdateOp = "$mul"
+	// UpMin is Min
+	UpMin UpdateOp = "$min"
+	// UpMax is Max
+	UpMax UpdateOp = "$max"
+	// UpRename is Rename
+	UpRename UpdateOp = "$rename"
+	// UpCurrentDate is Current date
+	UpCurrentDate UpdateOp = "$currentDate"
+	// UpPush is Push
+	UpPush UpdateOp = "$push"
+	// UpAddToSet is Add to set
+	UpAddToSet UpdateOp = "$addToSet"
+	// UpPull is Pull
+	UpPull UpdateOp = "$pull"
+	// UpPullAll is Pull all
+	UpPullAll UpdateOp = "$pullAll"
+	// UpPop is Pop
+	UpPop UpdateOp = "$pop"
+)
+
+// updateItem holding one field update
+type updateItem struct {
+	Op    UpdateOp
+	Field string
+	Value interface{}
+}
+
+// Update holding update operators and array filters, use it as data of Command Update
+type Update struct {
+	items        []updateItem
+	arrayFilters []*Filter
+}
+
+// NewUpdate = create new update builder
+func NewUpdate() *Update {
+	return new(Update)
+}
+
+// add = add field update
+func (u *Update) add(op UpdateOp, field string, v interface{}) *Update {
+	u.items = append(u.items, updateItem{
+		Op:    op,
+		Field: field,
+		Value: v,
+	})
+
+	return u
+}
+
+// Set = set field value
+func (u *Update) Set(field string, v interface{}) *Update {
+	return u.add(UpSet, field, v)
+}
+
+// SetOnInsert = set field value only when upsert inserts a document
+func (u *Update) SetOnInsert(field string, v interface{}) *Update {
+	return u.add(UpSetOnInsert, field, v)
+}
+
+// Unset = remove fields
+func (u *Update) Unset(fields ...string) *Update {
+	for _, field := range fields {
+		u.add(UpUnset, field, "")
+	}
+
+	return u
+}
+
+// Inc = increment field by v, use negative value to decrement
+func (u *Update) Inc(field string, v interface{}) *Update {
+	return u.add(UpInc, field, v)
+}
+
+// Mul = multiply field by v
+func (u *Update) Mul(field string, v interface{}) *Update {
+	return u.add(UpMul, field, v)
+}
+
+// Min = update field only if v is less than current value
+func (u *Update) Min(field string, v interface{}) *Update {
+	return u.add(UpMin, field, v)
+}
+
+// Max = update field only if v is greater than current value
+func (u *Update) Max(field string, v interface{}) *Update {
+	return u.add(UpMax, field, v)
+}
+
+// Rename = rename field
+func (u *Update) Rename(field, newName string) *Update {
+	return u.add(UpRename, field, newName)
+}
+
+// CurrentDate = set field to current date, dateType is "date" or "timestamp"
+func (u *Update) CurrentDate(field string, dateType string) *Update {
+	if dateType == "" {
+		dateType = "date"
+	}
+
+	return u.add(UpCurrentDate, field, bson.M{
+		"$type": strings.ToLower(dateType),
+	})
+}
+
+// Push = append values to array field
+func (u *Update) Push(field string, values ...interface{}) *Update {
+	return u.add(UpPush, field, values)
+}
+
+// AddToSet = add values to array field unless already exist
+func (u *Update) AddToSet(field string, values ...interface{}) *Update {
+	return u.add(UpAddToSet, field, values)
+}
+
+// Pull = remove all array elements equal to v
+func (u *Update) Pull(field string, v interface{}) *Update {
+	return u.add(UpPull, field, v)
+}
+
+// PullFilter = remove all array elements matching filter, filter fields are relative to the element
+func (u *Update) PullFilter(field string, filter *Filter) *Update {
+	return u.add(UpPull, field, filter)
+}
+
+// PullAll = remove all array elements equal to any of values
+func (u *Update) PullAll(field string, values ...interface{}) *Update {
+	return u.add(UpPullAll, field, values)
+}
+
+// Pop = remove first (first = true) or last element of array field
+func (u *Update) Pop(field string, first bool) *Update {
+	v := 1
+
+	if first {
+		v = -1
+	}
+
+	return u.add(UpPop, field, v)
+}
+
+// ArrayFilter = add filters for $[<identifier>] positional path, eg: Eq("elem.grade", 85) for "grades.$[elem]"
+func (u *Update) ArrayFilter(filters ...*Filter) *Update {
+	u.arrayFilters = append(u.arrayFilters, filters...)
+
+	return u
+}
+
+// each = wrap multiple values with $each
+func each(values []interface{}) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	return bson.M{
+		"$each": values,
+	}
+}
+
+// PathFirst = positional path of first matched array element, eg: grades.$.score
+func PathFirst(arrayField string, subFields ...string) string {
+	return strings.Join(append([]string{arrayField, "$"}, subFields...), ".")
+}
+
+// PathAll = positional path of all array elements, eg: grades.$[].score
+func PathAll(arrayField string, subFields ...string) string {
+	return strings.Join(append([]string{arrayField, "$[]"}, subFields...), ".")
+}
+
+// PathFiltered = positional path of array elements matching ArrayFilter identifier, eg: grades.$[elem].score
+func PathFiltered(arrayField, identifier string, subFields ...string) string {
+	return strings.Join(append([]string{arrayField, "$[" + identifier + "]"}, subFields...), ".")
+}
+
+// BuildUpdate = Build gom update to update document. Values of repeated Push, AddToSet and PullAll of the same field are combined,
+// other operators can't be repeated on the same field
+func BuildUpdate(u *Update) (bson.M, error) {
+	if u == nil || len(u.items) == 0 {
+		return nil, validationError("update can't be empty")
+	}
+
+	main := bson.M{}
+
+	for _, item := range u.items {
+		if item.Field == "" {
+			return nil, validationError(toolkit.Sprintf("field of %s can't be empty", item.Op))
+		}
+
+		inside, ok := main[string(item.Op)].(bson.M)
+
+		if !ok {
+			inside = bson.M{}
+			main[string(item.Op)] = inside
+		}
+
+		existing, exists := inside[item.Field]
+
+		switch item.Op {
+		case UpPush, UpAddToSet, UpPullAll:
+			values := item.Value.([]interface{})
+
+			if exists {
+				values = append(append([]interface{}{}, existing.([]interface{})...), values...)
+			}
+
+			inside[item.Field] = values
+			continue
+		case UpUnset:
+			inside[item.Field] = item.Value
+			continue
+		}
+
+		if exists {
+			return nil, validationError(toolkit.Sprintf("duplicate %s of field %s", item.Op, item.Field))
+		}
+
+		if f, ok := item.Value.(*Filter); ok {
+			cond, err := Compile(f)
+
+			if err != nil {
+				return nil, err
+			}
+
+			inside[item.Field] = cond
+		} else {
+			inside[item.Field] = item.Value
+		}
+	}
+
+	for _, op := range []UpdateOp{UpPush, UpAddToSet} {
+		inside, ok := main[string(op)].(bson.M)
+
+		if !ok {
+			continue
+		}
+
+		for field, values := range inside {
+			inside[field] = each(values.([]interface{}))
+		}
+	}
+
+	for _, f := range u.arrayFilters {
+		_, err := Compile(f)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return main, nil
+}
+
+// updateOptions = build update options with array filters
+func (u *Update) updateOptions() *options.UpdateOptions {
+	opts := options.Update()
+
+	if len(u.arrayFilters) > 0 {
+		filters := []interface{}{}
+
+		// array filters are checked by BuildUpdate
+		for _, f := range u.arrayFilters {
+			filter, _ := Compile(f)
+			filters = append(filters, filter)
+		}
+
+		opts.SetArrayFilters(options.ArrayFilters{
+			Filters: filters,
+		})
+	}
+
+	return opts
+}
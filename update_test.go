@@ -0,0 +1,86 @@
+package gom
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBuildUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		update *Update
+		want   bson.M
+	}{
+		{
+			name:   "push one value",
+			update: NewUpdate().Push("tags", "a"),
+			want:   bson.M{"$push": bson.M{"tags": "a"}},
+		},
+		{
+			name:   "repeated push is combined with $each",
+			update: NewUpdate().Push("tags", "a").Push("tags", "b", "c"),
+			want:   bson.M{"$push": bson.M{"tags": bson.M{"$each": []interface{}{"a", "b", "c"}}}},
+		},
+		{
+			name:   "repeated addToSet is combined with $each",
+			update: NewUpdate().AddToSet("tags", "a").AddToSet("tags", "b"),
+			want:   bson.M{"$addToSet": bson.M{"tags": bson.M{"$each": []interface{}{"a", "b"}}}},
+		},
+		{
+			name:   "repeated pullAll is combined",
+			update: NewUpdate().PullAll("tags", "a").PullAll("tags", "b"),
+			want:   bson.M{"$pullAll": bson.M{"tags": []interface{}{"a", "b"}}},
+		},
+		{
+			name:   "different fields of the same operator",
+			update: NewUpdate().Inc("n", 1).Inc("m", 2).Set("a", 1),
+			want:   bson.M{"$inc": bson.M{"n": 1, "m": 2}, "$set": bson.M{"a": 1}},
+		},
+		{
+			name:   "repeated unset",
+			update: NewUpdate().Unset("a", "a"),
+			want:   bson.M{"$unset": bson.M{"a": ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildUpdate(tt.update)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildUpdateErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		update *Update
+	}{
+		{name: "empty", update: NewUpdate()},
+		{name: "nil", update: nil},
+		{name: "empty field", update: NewUpdate().Set("", 1)},
+		{name: "repeated inc", update: NewUpdate().Inc("n", 1).Inc("n", 2)},
+		{name: "repeated set", update: NewUpdate().Set("a", 1).Set("a", 2)},
+		{name: "invalid pull filter", update: NewUpdate().PullFilter("tags", Eq("", 1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildUpdate(tt.update)
+
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("got %v, want validation error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package gom
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// FullDocumentDefault is full document only for insert and replace events
+	FullDocumentDefault = "default"
+	// FullDocumentUpdateLookup is lookup current full document for update events
+	FullDocumentUpdateLookup = "updateLookup"
+	// FullDocumentWhenAvailable is post-image for update events if available (MongoDB 6.0+)
+	FullDocumentWhenAvailable = "whenAvailable"
+	// FullDocumentRequired is post-image for update events, error if not available (MongoDB 6.0+)
+	FullDocumentRequired = "required"
+)
+
+// WatchParams = optional params of Watch
+type WatchParams struct {
+	// FullDocument = one of FullDocumentDefault, FullDocumentUpdateLookup, FullDocumentWhenAvailable, FullDocumentRequired
+	FullDocument string
+	// ResumeAfter = resume token of last processed event, stream continues after it
+	ResumeAfter bson.Raw
+	// StartAfter = like ResumeAfter but also works after invalidate event
+	StartAfter bson.Raw
+	// StartAtOperationTime = start from given cluster time
+	StartAtOperationTime *primitive.Timestamp
+	BatchSize            int32
+	MaxAwaitTime         time.Duration
+}
+
+// ChangeNamespace = database and collection of change event
+type ChangeNamespace struct {
+	Database   string `bson:"db"`
+	Collection string `bson:"coll"`
+}
+
+// UpdateDescription = updated and removed fields of update event
+type UpdateDescription struct {
+	UpdatedFields bson.M   `bson:"updatedFields"`
+	RemovedFields []string `bson:"removedFields"`
+}
+
+// ChangeEvent = change stream event
+type ChangeEvent struct {
+	// ResumeToken = store it and pass as WatchParams.ResumeAfter to resume
+	ResumeToken       bson.Raw            `bson:"_id"`
+	OperationType     string              `bson:"operationType"`
+	Namespace         ChangeNamespace     `bson:"ns"`
+	DocumentKey       bson.M              `bson:"documentKey"`
+	FullDocument      bson.Raw            `bson:"fullDocument"`
+	UpdateDescription *UpdateDescription  `bson:"updateDescription"`
+	ClusterTime       primitive.Timestamp `bson:"clusterTime"`
+}
+
+// Decode = decode full document of event into v
+func (e *ChangeEvent) Decode(v interface{}) error {
+	if len(e.FullDocument) == 0 {
+		return &CommandError{
+			Message: "Change event has no full document",
+			Kind:    ErrNotFound,
+		}
+	}
+
+	err := bson.Unmarshal(e.FullDocument, v)
+
+	if err != nil {
+		return wrapError("Decode error", err)
+	}
+
+	return nil
+}
+
+// changeStreamOptions = build driver change stream options
+func (p *WatchParams) changeStreamOptions() *options.ChangeStreamOptions {
+	opts := options.ChangeStream()
+
+	if p == nil {
+		return opts
+	}
+
+	if p.FullDocument != "" {
+		opts.SetFullDocument(options.FullDocument(p.FullDocument))
+	}
+
+	if p.ResumeAfter != nil {
+		opts.SetResumeAfter(p.ResumeAfter)
+	}
+
+	if p.StartAfter != nil {
+		opts.SetStartAfter(p.StartAfter)
+	}
+
+	if p.StartAtOperationTime != nil {
+		opts.SetStartAtOperationTime(p.StartAtOperationTime)
+	}
+
+	if p.BatchSize > 0 {
+		opts.SetBatchSize(p.BatchSize)
+	}
+
+	if p.MaxAwaitTime > 0 {
+		opts.SetMaxAwaitTime(p.MaxAwaitTime)
+	}
+
+	return opts
+}
+
+// buildWatchPipe = build change stream pipeline from pipe, or $match of filter. Filter fields are event fields, eg: operationType, fullDocument.Name
+func (s *Set) buildWatchPipe() []bson.M {
+	if s.pipe != nil {
+		return s.pipe
+	}
+
+	pipe := []bson.M{}
+
+	if len(s.filter.(bson.M)) > 0 {
+		pipe = append(pipe, bson.M{
+			"$match": s.filter,
+		})
+	}
+
+	return pipe
+}
+
+// Watch = watch changes of table, or whole database if table not set, and call fn for every event.
+// It blocks until Context is done (returns nil), fn returns error (ErrStop returns nil) or the stream fails. Timeout is ignored.
+// Cancel the context before Close, gom waits for running Watch while draining
+func (c *Command) Watch(params *WatchParams, fn func(event *ChangeEvent) error) error {
+	err := c.acquire()
+
+	if err != nil {
+		return err
+	}
+
+	defer c.set.gom.release()
+
+	client := c.set.gom.GetClient()
+
+	ctx := c.set.parentContext()
+
+	var stream *mongo.ChangeStream
+
+	if c.set.tableName == "" {
+		stream, err = client.Database(c.set.gom.GetDatabase()).Watch(ctx, c.set.buildWatchPipe(), params.changeStreamOptions())
+	} else {
+		stream, err = client.Database(c.set.gom.GetDatabase()).Collection(c.set.tableName).Watch(ctx, c.set.buildWatchPipe(), params.changeStreamOptions())
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return wrapError("Error watching changes", err)
+	}
+
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		event := new(ChangeEvent)
+
+		err = stream.Decode(event)
+
+		if err != nil {
+			return wrapError("Decode error", err)
+		}
+
+		err = fn(event)
+
+		if errors.Is(err, ErrStop) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return wrapError("Error watching changes", stream.Err())
+}